@@ -1,14 +1,28 @@
 package main
 
 import (
+	"context"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/database"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/postgres"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/stream"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -19,36 +33,172 @@ func main() {
 		log.Fatal("SERVER_ADDRESS not set")
 	}
 
-	tenderService := tender.NewService()
-	bidService := bid.NewService()
+	idempotencyTTL := durationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+	schedulerInterval := durationEnv("SCHEDULER_INTERVAL", time.Minute)
+	outboxInterval := durationEnv("OUTBOX_INTERVAL", time.Second)
+	webhookInterval := durationEnv("WEBHOOK_INTERVAL", 5*time.Second)
+	streamAccessTTL := durationEnv("STREAM_ACCESS_TTL", 30*time.Second)
 
-	commander := commands.NewCommander(db, tenderService, bidService)
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET not set")
+	}
+
+	// Page cursors can be signed with their own key and share the JWT one by
+	// default.
+	cursorSecret := os.Getenv("CURSOR_SECRET")
+	if cursorSecret == "" {
+		cursorSecret = jwtSecret
+	}
+
+	transactor := postgres.NewTransactor(db)
+	tenderRepository := postgres.NewTenderRepository(transactor)
+	bidRepository := postgres.NewBidRepository(transactor)
+	outboxRepository := postgres.NewOutboxRepository(transactor)
+
+	accessService := access.NewService(postgres.NewAccessRepository(transactor))
+	tenderService := tender.NewService(tenderRepository, accessService, outboxRepository)
+	bidService := bid.NewService(bidRepository, tenderRepository, tenderService, accessService, outboxRepository)
+	go tenderService.RunScheduler(context.Background(), schedulerInterval)
+
+	webhookService := webhook.NewService(postgres.NewWebhookRepository(transactor), accessService, &http.Client{Timeout: 10 * time.Second})
+	go webhookService.Run(context.Background(), webhookInterval)
+
+	eventRecorder := event.NewBus()
+	eventRecorder.Subscribe(webhookService.Enqueue)
+	eventBus := event.NewBus()
+	eventBroker := event.NewBroker()
+	eventBus.Subscribe(eventBroker.Publish)
+	relay := event.NewRelay(outboxRepository, eventRecorder, eventPublisher(eventBus))
+	go relay.Run(context.Background(), outboxInterval)
+
+	idempotencyService := idempotency.NewService(postgres.NewIdempotencyRepository(transactor), idempotencyTTL)
+	go idempotencyService.Cleanup(context.Background(), time.Hour)
+
+	authService := auth.NewService(postgres.NewAuthRepository(transactor), auth.Config{
+		Secret:        []byte(jwtSecret),
+		AccessTTL:     durationEnv("AUTH_ACCESS_TTL", 15*time.Minute),
+		RefreshTTL:    durationEnv("AUTH_REFRESH_TTL", 30*24*time.Hour),
+		AllowUsername: os.Getenv("AUTH_ALLOW_USERNAME") == "true",
+		SetupToken:    os.Getenv("AUTH_SETUP_TOKEN"),
+	})
+
+	apiKeyService := apikey.NewService(postgres.NewApiKeyRepository(transactor), accessService)
+	organizationService := organization.NewService(postgres.NewOrganizationRepository(transactor), accessService, authService)
+
+	commander := commands.NewCommander(tenderService, bidService, idempotencyService, webhookService, stream.NewService(outboxRepository, eventBroker, accessService, streamAccessTTL), authService, apiKeyService, organizationService, page.NewCodec([]byte(cursorSecret)))
 
 	router := gin.Default()
+	// Services look up request-scoped values set by the middleware, such as the
+	// API key organization, through the gin context.
+	router.ContextWithFallback = true
+	router.Use(commander.Authenticate)
 
 	tenderGroup := router.Group("/api/tenders")
 	bidGroup := router.Group("/api/bids")
+	employeeGroup := router.Group("/api/employees")
+	organizationGroup := router.Group("/api/organizations")
+	responsibleGroup := router.Group("/api/organizations/:organizationId/responsibles")
+	webhookGroup := router.Group("/api/organizations/:organizationId/webhooks")
+	apiKeyGroup := router.Group("/api/organizations/:organizationId/api-keys")
 
 	router.GET("/api/ping", commander.Ping)
+	router.POST("/api/auth/login", commander.Login)
+	router.POST("/api/auth/refresh", commander.RefreshToken)
+	router.PUT("/api/auth/password", commander.SetPassword)
+	router.PUT("/api/auth/password/setup", commander.SetupPassword)
+	router.GET("/api/events/stream", commander.EventStream)
 
 	tenderGroup.GET("", commander.ListAllTenders)
 	tenderGroup.GET("/my", commander.ListMyTenders)
 	tenderGroup.POST("/new", commander.AddTender)
+	tenderGroup.GET("/:tenderId", commander.GetTender)
 	tenderGroup.GET("/:tenderId/status", commander.TenderStatus)
 	tenderGroup.PUT("/:tenderId/status", commander.PutTenderStatus)
 	tenderGroup.PATCH("/:tenderId/edit", commander.PatchTender)
 	tenderGroup.PUT("/:tenderId/rollback/:version", commander.TenderRollback)
+	tenderGroup.GET("/:tenderId/versions", commander.TenderVersions)
+	tenderGroup.GET("/:tenderId/versions/:version", commander.TenderVersion)
+	tenderGroup.GET("/:tenderId/diff", commander.TenderDiff)
 
 	bidGroup.POST("/new", commander.AddBid)
 	bidGroup.GET("/my", commander.ListMy)
-	bidGroup.GET("/tender/:tenderId/list", commander.TenderIdList)
+	bidGroup.GET("/:bidId/list", commands.RenameParam("bidId", "tenderId"), commander.TenderIdList)
+	bidGroup.GET("/:bidId", commander.GetBid)
 	bidGroup.GET("/:bidId/status", commander.BidStatus)
 	bidGroup.PUT("/:bidId/status", commander.PutBidStatus)
 	bidGroup.PATCH("/:bidId/edit", commander.PatchBid)
-	bidGroup.PUT("/bids/:bidId/rollback/:version", commander.BidRollback)
+	bidGroup.PUT("/:bidId/submit_decision", commander.SubmitBidDecision)
+	bidGroup.PUT("/:bidId/feedback", commander.BidFeedback)
+	bidGroup.GET("/:bidId/reviews", commands.RenameParam("bidId", "tenderId"), commander.BidReviews)
+	bidGroup.PUT("/:bidId/rollback/:version", commander.BidRollback)
+	bidGroup.GET("/:bidId/versions", commander.BidVersions)
+	bidGroup.GET("/:bidId/versions/:version", commander.BidVersion)
+	bidGroup.GET("/:bidId/diff", commander.BidDiff)
+
+	employeeGroup.POST("", commander.AddEmployee)
+	employeeGroup.GET("", commander.ListEmployees)
+	employeeGroup.GET("/:employeeId", commander.GetEmployee)
+	employeeGroup.PATCH("/:employeeId", commander.PatchEmployee)
+	employeeGroup.DELETE("/:employeeId", commander.DeleteEmployee)
+
+	organizationGroup.POST("", commander.AddOrganization)
+	organizationGroup.GET("", commander.ListOrganizations)
+	organizationGroup.GET("/:organizationId", commander.GetOrganization)
+	organizationGroup.PATCH("/:organizationId", commander.PatchOrganization)
+	organizationGroup.DELETE("/:organizationId", commander.DeleteOrganization)
+
+	responsibleGroup.GET("", commander.ListResponsibles)
+	responsibleGroup.POST("", commander.AddResponsible)
+	responsibleGroup.DELETE("/:userId", commander.DeleteResponsible)
+
+	webhookGroup.GET("", commander.ListWebhooks)
+	webhookGroup.POST("", commander.AddWebhook)
+	webhookGroup.DELETE("/:webhookId", commander.DeleteWebhook)
+	webhookGroup.GET("/dead-letters", commander.WebhookDeadLetters)
+	webhookGroup.POST("/dead-letters/:deliveryId/redeliver", commander.RedeliverWebhook)
+
+	apiKeyGroup.GET("", commander.ListApiKeys)
+	apiKeyGroup.POST("", commander.AddApiKey)
+	apiKeyGroup.DELETE("/:keyId", commander.RevokeApiKey)
 
 	err := router.Run(serverAddress)
 	if err != nil {
 		log.Fatal("Error starting server:", err)
 	}
 }
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatal("Invalid "+key+":", value)
+	}
+
+	return duration
+}
+
+// eventPublisher adds the sink configured by EVENTS_SINK to bus: "stdout",
+// "file:<path>" or empty for in-process delivery only.
+func eventPublisher(bus *event.Bus) event.Publisher {
+	sink := os.Getenv("EVENTS_SINK")
+	switch {
+	case sink == "":
+		return bus
+	case sink == "stdout":
+		return event.Multi(bus, event.NewWriterPublisher(os.Stdout))
+	case strings.HasPrefix(sink, "file:"):
+		file, err := os.OpenFile(strings.TrimPrefix(sink, "file:"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatal("Error opening events file: ", err)
+		}
+		return event.Multi(bus, event.NewWriterPublisher(file))
+	}
+
+	log.Fatal("Invalid EVENTS_SINK:", sink)
+	return nil
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) AddApiKey(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var key apikey.Key
+	if err := ctx.ShouldBindJSON(&key); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	created, err := cmd.apiKeyService.Create(ctx, ctx.Param("organizationId"), cmd.getUsername(ctx, "username"), key)
+	respond(ctx, http.StatusCreated, created, err)
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) ListApiKeys(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	limit, offset, err := getPagination(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	keys, err := cmd.apiKeyService.List(ctx, ctx.Param("organizationId"), cmd.getUsername(ctx, "username"), limit, offset)
+	respond(ctx, http.StatusOK, keys, err)
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// Authenticate puts the principal of a bearer token or an X-API-Key header
+// into the request context. Requests without either pass through and are
+// identified by getUsername.
+func (cmd *Commander) Authenticate(ctx *gin.Context) {
+	header := ctx.GetHeader("Authorization")
+	key := ctx.GetHeader("X-API-Key")
+
+	switch {
+	case header != "" && key != "":
+		respondError(ctx, errs.Unauthorized("Use either a bearer token or an API key"))
+		ctx.Abort()
+		return
+	case header != "" && cmd.authService != nil:
+		cmd.authenticateToken(ctx, header)
+		return
+	case key != "" && cmd.apiKeyService != nil:
+		cmd.authenticateKey(ctx, key)
+		return
+	}
+
+	ctx.Next()
+}
+
+func (cmd *Commander) authenticateToken(ctx *gin.Context, header string) {
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found {
+		token = ""
+	}
+
+	principal, err := cmd.authService.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		respondError(ctx, err)
+		ctx.Abort()
+		return
+	}
+
+	ctx.Request = ctx.Request.WithContext(auth.WithPrincipal(ctx.Request.Context(), principal))
+	ctx.Next()
+}
+
+// authenticateKey lets an API key act as its creator within the key's
+// organization, on the routes its scopes allow.
+func (cmd *Commander) authenticateKey(ctx *gin.Context, key string) {
+	principal, err := cmd.apiKeyService.Authenticate(ctx, key)
+	if err != nil {
+		respondError(ctx, err)
+		ctx.Abort()
+		return
+	}
+
+	scope, ok := requiredScope(ctx)
+	if !ok {
+		respondError(ctx, errs.Forbidden("API keys can't be used for this endpoint"))
+		ctx.Abort()
+		return
+	}
+	if !principal.Allows(scope) {
+		respondError(ctx, errs.Forbidden("API key lacks the "+string(scope)+" scope"))
+		ctx.Abort()
+		return
+	}
+
+	requestCtx := access.WithOrganization(ctx.Request.Context(), principal.OrganizationId.String())
+	requestCtx = auth.WithPrincipal(requestCtx, auth.Principal{UserId: principal.UserId, Username: principal.Username})
+	ctx.Request = ctx.Request.WithContext(requestCtx)
+	ctx.Next()
+}
+
+// requiredScope maps the matched route to the API key scope it needs. Only
+// tender and bid routes are open to API keys.
+func requiredScope(ctx *gin.Context) (apikey.Scope, bool) {
+	path := strings.TrimPrefix(ctx.FullPath(), "/api")
+	read := ctx.Request.Method == http.MethodGet || ctx.Request.Method == http.MethodHead
+
+	switch {
+	case strings.HasPrefix(path, "/tenders") && read:
+		return apikey.ScopeTendersRead, true
+	case strings.HasPrefix(path, "/tenders"):
+		return apikey.ScopeTendersWrite, true
+	case strings.HasPrefix(path, "/bids") && read:
+		return apikey.ScopeBidsRead, true
+	case strings.HasPrefix(path, "/bids"):
+		return apikey.ScopeBidsWrite, true
+	}
+
+	return "", false
+}
+
+// getUsername returns the user a request acts on behalf of: the authenticated
+// principal or, in compatibility mode, the query parameter named param.
+func (cmd *Commander) getUsername(ctx *gin.Context, param string) string {
+	if principal, ok := auth.PrincipalFrom(ctx.Request.Context()); ok {
+		return principal.Username
+	}
+
+	if cmd.authService == nil || cmd.authService.AllowsUsername() {
+		return ctx.Query(param)
+	}
+
+	return ""
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) Login(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var credentials auth.Credentials
+	if err := ctx.ShouldBindJSON(&credentials); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	tokens, err := cmd.authService.Login(ctx, credentials)
+	respond(ctx, http.StatusOK, tokens, err)
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) SetPassword(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var change auth.PasswordChange
+	if err := ctx.ShouldBindJSON(&change); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	// Only a bearer token proves who is changing the password, never the
+	// username parameter.
+	principal, _ := auth.PrincipalFrom(ctx.Request.Context())
+	if err := cmd.authService.SetPassword(ctx, principal.Username, change); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (cmd *Commander) SetupPassword(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var setup auth.PasswordSetup
+	if err := ctx.ShouldBindJSON(&setup); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	if err := cmd.authService.SetupPassword(ctx, ctx.GetHeader("X-Setup-Token"), setup); err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
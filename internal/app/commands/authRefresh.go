@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) RefreshToken(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var body struct {
+		RefreshToken string `json:"refreshToken" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	tokens, err := cmd.authService.Refresh(ctx, body.RefreshToken)
+	respond(ctx, http.StatusOK, tokens, err)
+}
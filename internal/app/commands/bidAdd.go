@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +16,14 @@ func (cmd *Commander) AddBid(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.Add(cmd.db, ctx)
+	cmd.withIdempotency(ctx, "bids/new", func() {
+		var newBid bid.Bid
+		if err := ctx.ShouldBindJSON(&newBid); err != nil {
+			respondError(ctx, errs.Validation("Invalid request data"))
+			return
+		}
+
+		created, err := cmd.bidService.Add(ctx, cmd.getUsername(ctx, "username"), newBid)
+		respondWithETag(ctx, http.StatusCreated, created, created.Version, err)
+	})
 }
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) BidFeedback(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	updated, err := cmd.bidService.Feedback(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), ctx.Query("bidFeedback"))
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
+}
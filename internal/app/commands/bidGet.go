@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) GetBid(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	found, err := cmd.bidService.Get(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"))
+	respondWithETag(ctx, http.StatusOK, found, found.Version, err)
+}
@@ -14,5 +14,15 @@ func (cmd *Commander) ListMy(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.ListMy(cmd.db, ctx)
+	username := cmd.getUsername(ctx, "username")
+	scope := pageScope("bids/my", username)
+
+	request, err := cmd.getPage(ctx, scope)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	bids, err := cmd.bidService.ListMy(ctx, username, request)
+	respondPage(ctx, cmd.cursors, scope, bids, err)
 }
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +16,18 @@ func (cmd *Commander) PatchBid(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.Patch(cmd.db, ctx)
+	expectedVersion, err := getIfMatch(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	var bidPatch bid.BidPatch
+	if err = ctx.ShouldBindJSON(&bidPatch); err != nil {
+		respondError(ctx, errs.Validation("Invalid request body"))
+		return
+	}
+
+	updated, err := cmd.bidService.Patch(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), bidPatch, expectedVersion)
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
 }
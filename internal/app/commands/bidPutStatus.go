@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +15,12 @@ func (cmd *Commander) PutBidStatus(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.PutStatus(cmd.db, ctx)
+	expectedVersion, err := getIfMatch(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	updated, err := cmd.bidService.PutStatus(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), bid.BidStatus(ctx.Query("status")), expectedVersion)
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
 }
@@ -14,5 +14,18 @@ func (cmd *Commander) BidRollback(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.Rollback(cmd.db, ctx)
+	expectedVersion, err := getIfMatch(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	version, err := getVersion(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	updated, err := cmd.bidService.Rollback(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), version, expectedVersion)
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
 }
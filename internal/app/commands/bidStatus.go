@@ -14,5 +14,6 @@ func (cmd *Commander) BidStatus(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.Status(cmd.db, ctx)
+	status, version, err := cmd.bidService.Status(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"))
+	respondWithETag(ctx, http.StatusOK, status, version, err)
 }
@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) SubmitBidDecision(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	updated, err := cmd.bidService.SubmitDecision(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), bid.BidDecision(ctx.Query("decision")))
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
+}
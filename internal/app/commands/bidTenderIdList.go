@@ -14,5 +14,16 @@ func (cmd *Commander) TenderIdList(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.bidService.TenderIdList(cmd.db, ctx)
+	tenderId := ctx.Param("tenderId")
+	username := cmd.getUsername(ctx, "username")
+	scope := pageScope("bids/tender", tenderId, username)
+
+	request, err := cmd.getPage(ctx, scope)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	bids, err := cmd.bidService.TenderIdList(ctx, tenderId, username, request)
+	respondPage(ctx, cmd.cursors, scope, bids, err)
 }
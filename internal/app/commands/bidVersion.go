@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) BidVersion(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	version, err := getVersion(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	snapshot, err := cmd.bidService.Version(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), version)
+	respondWithETag(ctx, http.StatusOK, snapshot, snapshot.Version, err)
+}
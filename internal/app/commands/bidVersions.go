@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) BidVersions(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	limit, offset, err := getPagination(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	versions, current, err := cmd.bidService.Versions(ctx, ctx.Param("bidId"), cmd.getUsername(ctx, "username"), limit, offset)
+	respondWithETag(ctx, http.StatusOK, versions, current, err)
+}
@@ -1,21 +1,39 @@
 package commands
 
 import (
-	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/stream"
 	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
 )
 
 type Commander struct {
-	db            *sql.DB
-	tenderService *tender.Service
-	bidService    *bid.Service
+	tenderService       *tender.Service
+	bidService          *bid.Service
+	idempotencyService  *idempotency.Service
+	webhookService      *webhook.Service
+	streamService       *stream.Service
+	authService         *auth.Service
+	apiKeyService       *apikey.Service
+	organizationService *organization.Service
+	cursors             *page.Codec
 }
 
-func NewCommander(db *sql.DB, tenderService *tender.Service, bidService *bid.Service) *Commander {
+func NewCommander(tenderService *tender.Service, bidService *bid.Service, idempotencyService *idempotency.Service, webhookService *webhook.Service, streamService *stream.Service, authService *auth.Service, apiKeyService *apikey.Service, organizationService *organization.Service, cursors *page.Codec) *Commander {
 	return &Commander{
-		db:            db,
-		tenderService: tenderService,
-		bidService:    bidService,
+		tenderService:       tenderService,
+		bidService:          bidService,
+		idempotencyService:  idempotencyService,
+		webhookService:      webhookService,
+		streamService:       streamService,
+		authService:         authService,
+		apiKeyService:       apiKeyService,
+		organizationService: organizationService,
+		cursors:             cursors,
 	}
 }
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) DeleteEmployee(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	deleted, err := cmd.organizationService.DeleteEmployee(ctx, ctx.Param("employeeId"), cmd.getUsername(ctx, "username"))
+	respond(ctx, http.StatusOK, deleted, err)
+}
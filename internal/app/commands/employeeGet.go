@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) GetEmployee(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	employee, err := cmd.organizationService.GetEmployee(ctx, ctx.Param("employeeId"), cmd.getUsername(ctx, "username"))
+	respond(ctx, http.StatusOK, employee, err)
+}
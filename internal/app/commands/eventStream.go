@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/stream"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const streamHeartbeat = 15 * time.Second
+
+func (cmd *Commander) EventStream(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	lastEventId, err := getLastEventId(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	filter := stream.Filter{
+		OrganizationId: ctx.Query("organizationId"),
+		TenderId:       ctx.Query("tenderId"),
+		Types:          getEventTypes(ctx),
+	}
+
+	subscription, err := cmd.streamService.Open(ctx.Request.Context(), cmd.getUsername(ctx, "username"), filter, lastEventId)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+	defer subscription.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case e, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+
+			ctx.Render(-1, sse.Event{Id: strconv.FormatInt(e.Sequence, 10), Event: string(e.Type), Data: e})
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// getLastEventId reads the sequence a reconnecting client saw last, sent to it
+// as the event id, from the Last-Event-ID header or, for clients that can't set
+// headers, the lastEventId query parameter.
+func getLastEventId(ctx *gin.Context) (int64, error) {
+	value := ctx.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = ctx.Query("lastEventId")
+	}
+
+	if value == "" {
+		return stream.FromNow, nil
+	}
+
+	lastEventId, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || lastEventId < 0 {
+		return 0, errs.Validation("Invalid Last-Event-ID")
+	}
+
+	return lastEventId, nil
+}
+
+func getEventTypes(ctx *gin.Context) []event.Type {
+	var types []event.Type
+	for _, value := range ctx.QueryArray("type") {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType != "" {
+				types = append(types, event.Type(eventType))
+			}
+		}
+	}
+
+	return types
+}
@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func respondError(ctx *gin.Context, err error) {
+	ctx.Header("Content-Type", "application/json")
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errs.ErrValidation):
+		status = http.StatusBadRequest
+	case errors.Is(err, errs.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, errs.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, errs.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errs.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, errs.ErrPrecondition):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, errs.ErrUnprocessable):
+		status = http.StatusUnprocessableEntity
+	}
+
+	body := gin.H{"reason": err.Error()}
+
+	var detailed *errs.Error
+	if errors.As(err, &detailed) {
+		for key, value := range detailed.Details() {
+			body[key] = value
+		}
+	}
+
+	ctx.IndentedJSON(status, body)
+}
+
+func respond(ctx *gin.Context, status int, body any, err error) {
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/json")
+	ctx.IndentedJSON(status, body)
+}
+
+func respondWithETag(ctx *gin.Context, status int, body any, version int, err error) {
+	if err == nil {
+		ctx.Header("ETag", etag(version))
+	}
+
+	respond(ctx, status, body, err)
+}
+
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// getIfMatch returns the entity version the client expects to modify, or 0
+// when the request carries no precondition. Only strong single-version tags
+// produced by etag are accepted.
+func getIfMatch(ctx *gin.Context) (int, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, errs.Validation("Invalid If-Match header")
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, errs.Validation("Invalid If-Match header")
+	}
+
+	return version, nil
+}
+
+func getLimit(ctx *gin.Context) (int, error) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+	if err != nil {
+		return 0, errs.Validation("Invalid limit value")
+	}
+
+	return limit, nil
+}
+
+func getOffset(ctx *gin.Context) (int, error) {
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil {
+		return 0, errs.Validation("Invalid offset value")
+	}
+
+	return offset, nil
+}
+
+func getPagination(ctx *gin.Context) (int, int, error) {
+	limit, err := getLimit(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offset, err := getOffset(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return limit, offset, nil
+}
+
+// getPage reads the limit and either the offset or the cursor of a previous
+// page of the list identified by scope. Offsets are kept for clients that
+// predate cursors.
+func (cmd *Commander) getPage(ctx *gin.Context, scope string) (page.Request, error) {
+	limit, offset, err := getPagination(ctx)
+	if err != nil {
+		return page.Request{}, err
+	}
+
+	cursor := ctx.Query("cursor")
+	if cursor == "" {
+		return page.Request{Limit: limit, Offset: offset}, nil
+	}
+
+	if offset != 0 {
+		return page.Request{}, errs.Validation("Use either offset or cursor")
+	}
+
+	after, err := cmd.cursors.Decode(scope, cursor)
+	if err != nil {
+		return page.Request{}, err
+	}
+
+	return page.Request{Limit: limit, After: &after}, nil
+}
+
+// pageScope identifies a list by its name and the filters that select its
+// items, so a cursor can't be replayed against another list.
+func pageScope(list string, filters ...string) string {
+	return strings.Join(append([]string{list}, filters...), "\n")
+}
+
+// respondPage responds with the items of p. The total count and the cursor of
+// the next page go into headers so the body stays a plain array.
+func respondPage[T any](ctx *gin.Context, cursors *page.Codec, scope string, p page.Page[T], err error) {
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	ctx.Header("X-Total-Count", strconv.Itoa(p.Total))
+
+	if p.Next != nil {
+		next := cursors.Encode(scope, *p.Next)
+
+		link := *ctx.Request.URL
+		query := link.Query()
+		query.Del("offset")
+		query.Set("cursor", next)
+		link.RawQuery = query.Encode()
+
+		ctx.Header("X-Next-Cursor", next)
+		ctx.Header("Link", "<"+link.RequestURI()+`>; rel="next"`)
+	}
+
+	respond(ctx, http.StatusOK, p.Items, nil)
+}
+
+func getVersion(ctx *gin.Context) (int, error) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		return 0, errs.Validation("Version must be >= 1")
+	}
+
+	return version, nil
+}
+
+func getVersionQuery(ctx *gin.Context, key string) (int, error) {
+	version, err := strconv.Atoi(ctx.Query(key))
+	if err != nil {
+		return 0, errs.Validation("Invalid " + key + " version")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+)
+
+var replayedHeaders = []string{"Content-Type", "ETag"}
+
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// withIdempotency runs handle at most once per Idempotency-Key within scope.
+// Successful responses are recorded and replayed to retries of the same
+// request; failed ones release the key so the client can try again.
+func (cmd *Commander) withIdempotency(ctx *gin.Context, scope string, handle func()) {
+	key := ctx.GetHeader("Idempotency-Key")
+	if key == "" || cmd.idempotencyService == nil {
+		handle()
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	io.WriteString(hash, ctx.Request.Method+" "+ctx.Request.URL.RequestURI()+"\n")
+	hash.Write(body)
+	fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+	// Keys are chosen by clients, so authenticated users get their own
+	// namespace and can't replay each other's responses.
+	if principal, ok := auth.PrincipalFrom(ctx.Request.Context()); ok {
+		scope += ":" + principal.UserId
+	}
+
+	response, err := cmd.idempotencyService.Begin(ctx, scope, key, fingerprint)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	if response != nil {
+		for name, value := range response.Headers {
+			ctx.Header(name, value)
+		}
+		ctx.Header("Idempotent-Replayed", "true")
+		ctx.Data(response.StatusCode, response.Headers["Content-Type"], response.Body)
+		return
+	}
+
+	writer := &recordingWriter{ResponseWriter: ctx.Writer}
+	ctx.Writer = writer
+
+	finishCtx := context.WithoutCancel(ctx.Request.Context())
+	completed := false
+	defer func() {
+		ctx.Writer = writer.ResponseWriter
+
+		if !completed || writer.Status() >= http.StatusMultipleChoices {
+			if err := cmd.idempotencyService.Release(finishCtx, scope, key); err != nil {
+				log.Println("Error releasing idempotency key:", err)
+			}
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+
+		err := cmd.idempotencyService.Complete(finishCtx, scope, key, idempotency.Response{
+			StatusCode: writer.Status(),
+			Headers:    headers,
+			Body:       writer.body.Bytes(),
+		})
+		if err != nil {
+			log.Println("Error storing idempotent response:", err)
+		}
+	}()
+
+	handle()
+	completed = true
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) AddOrganization(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var newOrganization organization.Organization
+	if err := ctx.ShouldBindJSON(&newOrganization); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	created, err := cmd.organizationService.CreateOrganization(ctx, cmd.getUsername(ctx, "username"), newOrganization)
+	respond(ctx, http.StatusCreated, created, err)
+}
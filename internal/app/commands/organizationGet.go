@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) GetOrganization(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	found, err := cmd.organizationService.GetOrganization(ctx, ctx.Param("organizationId"), cmd.getUsername(ctx, "username"))
+	respond(ctx, http.StatusOK, found, err)
+}
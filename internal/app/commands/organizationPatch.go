@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) PatchOrganization(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	var patch organization.OrganizationPatch
+	if err := ctx.ShouldBindJSON(&patch); err != nil {
+		respondError(ctx, errs.Validation("Invalid request data"))
+		return
+	}
+
+	updated, err := cmd.organizationService.UpdateOrganization(ctx, ctx.Param("organizationId"), cmd.getUsername(ctx, "username"), patch)
+	respond(ctx, http.StatusOK, updated, err)
+}
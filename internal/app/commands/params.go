@@ -0,0 +1,18 @@
+package commands
+
+import "github.com/gin-gonic/gin"
+
+// RenameParam exposes the path parameter from under the name to. Gin does not
+// allow sibling routes to use differently named wildcards, so routes such as
+// /bids/{tenderId}/reviews are registered with the bidId wildcard and renamed.
+func RenameParam(from string, to string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for i, param := range ctx.Params {
+			if param.Key == from {
+				ctx.Params[i].Key = to
+			}
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) DeleteResponsible(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	removed, err := cmd.organizationService.RemoveResponsible(ctx, ctx.Param("organizationId"), ctx.Param("userId"), cmd.getUsername(ctx, "username"))
+	respond(ctx, http.StatusOK, removed, err)
+}
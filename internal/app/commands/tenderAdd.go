@@ -2,6 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +17,19 @@ func (cmd *Commander) AddTender(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.Add(cmd.db, ctx)
+	cmd.withIdempotency(ctx, "tenders/new", func() {
+		var newTender tender.Tender
+		if err := ctx.ShouldBindJSON(&newTender); err != nil {
+			respondError(ctx, errs.Validation("Invalid request data"))
+			return
+		}
+
+		if principal, ok := auth.PrincipalFrom(ctx.Request.Context()); ok && principal.Username != newTender.CreatorUsername {
+			respondError(ctx, errs.Forbidden("Tender can only be created on behalf of the authenticated user"))
+			return
+		}
+
+		created, err := cmd.tenderService.Add(ctx, newTender)
+		respondWithETag(ctx, http.StatusCreated, created, created.Version, err)
+	})
 }
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) TenderDiff(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	from, err := getVersionQuery(ctx, "from")
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	to, err := getVersionQuery(ctx, "to")
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	diff, err := cmd.tenderService.Diff(ctx, ctx.Param("tenderId"), cmd.getUsername(ctx, "username"), from, to)
+	respond(ctx, http.StatusOK, diff, err)
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +15,15 @@ func (cmd *Commander) ListAllTenders(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.ListAll(cmd.db, ctx)
+	serviceType := ctx.Query("service_type")
+	scope := pageScope("tenders", serviceType)
+
+	request, err := cmd.getPage(ctx, scope)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	tenders, err := cmd.tenderService.ListAll(ctx, tender.TenderServiceType(serviceType), request)
+	respondPage(ctx, cmd.cursors, scope, tenders, err)
 }
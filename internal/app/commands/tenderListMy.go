@@ -14,5 +14,15 @@ func (cmd *Commander) ListMyTenders(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.ListMy(cmd.db, ctx)
+	username := cmd.getUsername(ctx, "username")
+	scope := pageScope("tenders/my", username)
+
+	request, err := cmd.getPage(ctx, scope)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	tenders, err := cmd.tenderService.ListMy(ctx, username, request)
+	respondPage(ctx, cmd.cursors, scope, tenders, err)
 }
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -14,5 +16,18 @@ func (cmd *Commander) PatchTender(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.Patch(cmd.db, ctx)
+	expectedVersion, err := getIfMatch(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	var tenderPatch tender.TenderPatch
+	if err = ctx.ShouldBindJSON(&tenderPatch); err != nil {
+		respondError(ctx, errs.Validation("Invalid request body"))
+		return
+	}
+
+	updated, err := cmd.tenderService.Patch(ctx, ctx.Param("tenderId"), cmd.getUsername(ctx, "username"), tenderPatch, expectedVersion)
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
 }
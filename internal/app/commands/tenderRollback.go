@@ -14,5 +14,18 @@ func (cmd *Commander) TenderRollback(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.Rollback(cmd.db, ctx)
+	expectedVersion, err := getIfMatch(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	version, err := getVersion(ctx)
+	if err != nil {
+		respondError(ctx, err)
+		return
+	}
+
+	updated, err := cmd.tenderService.Rollback(ctx, ctx.Param("tenderId"), cmd.getUsername(ctx, "username"), version, expectedVersion)
+	respondWithETag(ctx, http.StatusOK, updated, updated.Version, err)
 }
@@ -14,5 +14,6 @@ func (cmd *Commander) TenderStatus(ctx *gin.Context) {
 		}
 	}()
 
-	cmd.tenderService.Status(cmd.db, ctx)
+	status, version, err := cmd.tenderService.Status(ctx, ctx.Param("tenderId"), cmd.getUsername(ctx, "username"))
+	respondWithETag(ctx, http.StatusOK, status, version, err)
 }
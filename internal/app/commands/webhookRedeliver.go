@@ -0,0 +1,19 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (cmd *Commander) RedeliverWebhook(ctx *gin.Context) {
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Recovered from panic: %v", panicValue)})
+			return
+		}
+	}()
+
+	delivery, err := cmd.webhookService.Redeliver(ctx, ctx.Param("organizationId"), ctx.Param("deliveryId"), cmd.getUsername(ctx, "username"))
+	respond(ctx, http.StatusOK, delivery, err)
+}
@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS bid_decision;
+
+DROP TYPE IF EXISTS bid_decision;
+
+UPDATE bid SET status = 'Published' WHERE status IN ('Approved', 'Rejected');
+UPDATE bid_diff SET status = 'Published' WHERE status IN ('Approved', 'Rejected');
+
+ALTER TYPE bid_status RENAME TO bid_status_old;
+
+CREATE TYPE bid_status AS ENUM (
+    'Created',
+    'Published',
+    'Cancelled'
+);
+
+ALTER TABLE bid ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+ALTER TABLE bid_diff ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+
+DROP TYPE bid_status_old;
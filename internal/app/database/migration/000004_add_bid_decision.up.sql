@@ -0,0 +1,16 @@
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Approved';
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'Rejected';
+
+CREATE TYPE bid_decision AS ENUM (
+    'Approved',
+    'Rejected'
+);
+
+CREATE TABLE bid_decision (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    decision bid_decision NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bid_id, user_id)
+);
@@ -0,0 +1,27 @@
+ALTER TABLE bid_diff DROP CONSTRAINT IF EXISTS bid_diff_id_version_key;
+
+ALTER TABLE bid_diff ALTER COLUMN tender_id DROP NOT NULL;
+
+DELETE FROM bid_diff d
+USING bid_diff_legacy l
+WHERE d.id = l.id;
+
+INSERT INTO bid_diff (id, name, description, status, tender_id, author_type, author_id, version, created_at)
+SELECT id, name, description, status::bid_status, tender_id, author_type::author_type, author_id, version, created_at
+FROM bid_diff_legacy
+WHERE id IN (SELECT id FROM bid);
+
+DROP TABLE bid_diff_legacy;
+
+ALTER TABLE tender_diff DROP CONSTRAINT IF EXISTS tender_diff_id_version_key;
+
+DELETE FROM tender_diff d
+USING tender_diff_legacy l
+WHERE d.id = l.id;
+
+INSERT INTO tender_diff (id, name, description, status, service_type, version, organization_id, creator_username, created_at)
+SELECT id, name, description, status::tender_status, service_type::service_type, version, organization_id, creator_username, created_at
+FROM tender_diff_legacy
+WHERE id IN (SELECT id FROM tender);
+
+DROP TABLE tender_diff_legacy;
@@ -0,0 +1,94 @@
+-- Legacy history rows are labelled with the version an update produced. Add and
+-- Patch stored the state at that version and are kept as they are. PutStatus
+-- stored the state before the update, which differs from the state at its
+-- label only in status. Rollback stored the target snapshot under target + 1,
+-- next to the row that already had that label, and left its own version
+-- without a row.
+
+-- Rollback rows can't be told apart from the rows they collide with, so the
+-- history of an entity without exactly one row per version from 1 to its
+-- current version is moved here unchanged and restarted from the current row.
+-- Enum columns are stored as text to keep later type changes independent.
+CREATE TABLE tender_diff_legacy AS
+SELECT id, name, description, status::text AS status, service_type::text AS service_type, version, organization_id, creator_username, created_at
+FROM tender_diff
+WITH NO DATA;
+
+WITH broken AS (
+    SELECT t.id
+    FROM tender t
+    LEFT JOIN tender_diff d ON d.id = t.id
+    GROUP BY t.id, t.version
+    HAVING COUNT(d.id) <> t.version
+        OR COUNT(DISTINCT d.version) <> t.version
+        OR MIN(d.version) <> 1
+        OR MAX(d.version) <> t.version
+), moved AS (
+    DELETE FROM tender_diff d
+    USING broken b
+    WHERE d.id = b.id
+    RETURNING d.*
+)
+INSERT INTO tender_diff_legacy
+SELECT id, name, description, status::text, service_type::text, version, organization_id, creator_username, created_at
+FROM moved;
+
+-- In an intact history only PutStatus rows are wrong. The status at version N
+-- is the one stored in row N + 1, which Patch copied and PutStatus recorded as
+-- the state before its update, or the current status for the latest version.
+UPDATE tender_diff d
+SET status = COALESCE(
+    (SELECT n.status FROM tender_diff n WHERE n.id = d.id AND n.version = d.version + 1),
+    (SELECT t.status FROM tender t WHERE t.id = d.id AND t.version = d.version)
+);
+
+INSERT INTO tender_diff (id, name, description, status, service_type, version, organization_id, creator_username, created_at)
+SELECT id, name, description, status, service_type, version, organization_id, creator_username, created_at
+FROM tender t
+WHERE NOT EXISTS (SELECT 1 FROM tender_diff d WHERE d.id = t.id);
+
+ALTER TABLE tender_diff ADD CONSTRAINT tender_diff_id_version_key UNIQUE (id, version);
+
+CREATE TABLE bid_diff_legacy AS
+SELECT id, name, description, status::text AS status, tender_id, author_type::text AS author_type, author_id, version, created_at
+FROM bid_diff
+WITH NO DATA;
+
+WITH broken AS (
+    SELECT b.id
+    FROM bid b
+    LEFT JOIN bid_diff d ON d.id = b.id
+    GROUP BY b.id, b.version
+    HAVING COUNT(d.id) <> b.version
+        OR COUNT(DISTINCT d.version) <> b.version
+        OR MIN(d.version) <> 1
+        OR MAX(d.version) <> b.version
+), moved AS (
+    DELETE FROM bid_diff d
+    USING broken b
+    WHERE d.id = b.id
+    RETURNING d.*
+)
+INSERT INTO bid_diff_legacy
+SELECT id, name, description, status::text, tender_id, author_type::text, author_id, version, created_at
+FROM moved;
+
+UPDATE bid_diff d
+SET tender_id = b.tender_id
+FROM bid b
+WHERE d.id = b.id AND d.tender_id IS NULL;
+
+UPDATE bid_diff d
+SET status = COALESCE(
+    (SELECT n.status FROM bid_diff n WHERE n.id = d.id AND n.version = d.version + 1),
+    (SELECT b.status FROM bid b WHERE b.id = d.id AND b.version = d.version)
+);
+
+INSERT INTO bid_diff (id, name, description, status, tender_id, author_type, author_id, version, created_at)
+SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at
+FROM bid b
+WHERE NOT EXISTS (SELECT 1 FROM bid_diff d WHERE d.id = b.id);
+
+ALTER TABLE bid_diff ALTER COLUMN tender_id SET NOT NULL;
+
+ALTER TABLE bid_diff ADD CONSTRAINT bid_diff_id_version_key UNIQUE (id, version);
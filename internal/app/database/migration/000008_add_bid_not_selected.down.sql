@@ -0,0 +1,17 @@
+UPDATE bid SET status = 'Published' WHERE status = 'NotSelected';
+UPDATE bid_diff SET status = 'Published' WHERE status = 'NotSelected';
+
+ALTER TYPE bid_status RENAME TO bid_status_old;
+
+CREATE TYPE bid_status AS ENUM (
+    'Created',
+    'Published',
+    'Cancelled',
+    'Approved',
+    'Rejected'
+);
+
+ALTER TABLE bid ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+ALTER TABLE bid_diff ALTER COLUMN status TYPE bid_status USING status::text::bid_status;
+
+DROP TYPE bid_status_old;
@@ -0,0 +1 @@
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'NotSelected';
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS tender_close_at_idx;
+DROP INDEX IF EXISTS tender_publish_at_idx;
+
+ALTER TABLE tender_diff
+    DROP COLUMN close_at,
+    DROP COLUMN bid_deadline,
+    DROP COLUMN publish_at;
+
+ALTER TABLE tender
+    DROP COLUMN close_at,
+    DROP COLUMN bid_deadline,
+    DROP COLUMN publish_at;
@@ -0,0 +1,12 @@
+ALTER TABLE tender
+    ADD COLUMN publish_at TIMESTAMP,
+    ADD COLUMN bid_deadline TIMESTAMP,
+    ADD COLUMN close_at TIMESTAMP;
+
+ALTER TABLE tender_diff
+    ADD COLUMN publish_at TIMESTAMP,
+    ADD COLUMN bid_deadline TIMESTAMP,
+    ADD COLUMN close_at TIMESTAMP;
+
+CREATE INDEX tender_publish_at_idx ON tender (publish_at) WHERE status = 'Created' AND publish_at IS NOT NULL;
+CREATE INDEX tender_close_at_idx ON tender (close_at) WHERE status <> 'Closed' AND close_at IS NOT NULL;
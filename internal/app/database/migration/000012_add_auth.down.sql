@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS refresh_token;
+ALTER TABLE employee DROP COLUMN IF EXISTS password_hash;
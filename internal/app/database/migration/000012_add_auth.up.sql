@@ -0,0 +1,12 @@
+ALTER TABLE employee ADD COLUMN password_hash VARCHAR(100);
+
+CREATE TABLE refresh_token (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    employee_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refresh_token_employee_id_idx ON refresh_token (employee_id);
@@ -0,0 +1,14 @@
+CREATE TABLE api_key (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    scopes VARCHAR(50)[] NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    created_by UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX api_key_organization_id_idx ON api_key (organization_id);
@@ -0,0 +1,2 @@
+ALTER TABLE organization_responsible DROP COLUMN IF EXISTS role;
+DROP TYPE IF EXISTS organization_role;
@@ -0,0 +1,9 @@
+CREATE TYPE organization_role AS ENUM (
+    'Owner',
+    'ProcurementManager',
+    'Evaluator',
+    'Viewer'
+);
+
+-- Responsibles had full access so far, which is what owners keep.
+ALTER TABLE organization_responsible ADD COLUMN role organization_role NOT NULL DEFAULT 'Owner';
@@ -0,0 +1,3 @@
+ALTER TABLE organization_responsible DROP CONSTRAINT IF EXISTS organization_responsible_user_id_key;
+ALTER TABLE organization_responsible ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE organization_responsible ALTER COLUMN organization_id DROP NOT NULL;
@@ -0,0 +1,29 @@
+-- An employee is responsible for at most one organization. Rows without an
+-- organization or an employee grant nothing, and a responsibility recorded
+-- twice is kept once.
+DELETE FROM organization_responsible WHERE organization_id IS NULL OR user_id IS NULL;
+
+DELETE FROM organization_responsible r
+USING organization_responsible o
+WHERE r.user_id = o.user_id AND r.organization_id = o.organization_id AND r.id > o.id;
+
+-- Which of several organizations an employee should stay in can't be decided
+-- here, so such employees stop the migration until they are resolved by hand.
+DO $$
+DECLARE
+    usernames TEXT;
+BEGIN
+    SELECT string_agg(e.username, ', ' ORDER BY e.username) INTO usernames
+    FROM employee e
+    WHERE (SELECT COUNT(*) FROM organization_responsible r WHERE r.user_id = e.id) > 1;
+
+    IF usernames IS NOT NULL THEN
+        RAISE EXCEPTION 'Employees responsible for more than one organization: %', usernames
+            USING HINT = 'Keep one organization_responsible row per employee and run the migration again.';
+    END IF;
+END
+$$;
+
+ALTER TABLE organization_responsible ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE organization_responsible ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE organization_responsible ADD CONSTRAINT organization_responsible_user_id_key UNIQUE (user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bid_tender_id_name_id_idx;
+DROP INDEX IF EXISTS tender_creator_username_name_id_idx;
+DROP INDEX IF EXISTS tender_published_name_id_idx;
@@ -0,0 +1,5 @@
+-- Lists are ordered by name and id and continue after the last pair of the
+-- previous page.
+CREATE INDEX tender_published_name_id_idx ON tender (name, id) WHERE status = 'Published';
+CREATE INDEX tender_creator_username_name_id_idx ON tender (creator_username, name, id);
+CREATE INDEX bid_tender_id_name_id_idx ON bid (tender_id, name, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS outbox_sequence_idx;
+ALTER TABLE outbox DROP COLUMN IF EXISTS sequence;
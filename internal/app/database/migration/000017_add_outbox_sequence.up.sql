@@ -0,0 +1,14 @@
+-- The relay numbers events as it publishes them, one batch at a time, so the
+-- sequence follows the order publications commit in, which ids don't.
+ALTER TABLE outbox ADD COLUMN sequence BIGINT;
+
+UPDATE outbox o
+SET sequence = n.sequence
+FROM (
+    SELECT id, row_number() OVER (ORDER BY id) AS sequence
+    FROM outbox
+    WHERE published_at IS NOT NULL
+) n
+WHERE o.id = n.id;
+
+CREATE UNIQUE INDEX outbox_sequence_idx ON outbox (sequence);
@@ -0,0 +1,3 @@
+ALTER TABLE outbox DROP COLUMN IF EXISTS public;
+ALTER TABLE outbox DROP COLUMN IF EXISTS author_id;
+ALTER TABLE outbox DROP COLUMN IF EXISTS author_type;
@@ -0,0 +1,17 @@
+-- Event streams show bid events to the bid's author and changes to published
+-- tenders to everyone. Earlier events are classified from their payloads,
+-- which hold the bid, directly or under "bid", or the tender after the change.
+-- A closed tender doesn't show whether it was published, so its earlier close
+-- events stay private.
+ALTER TABLE outbox ADD COLUMN author_type VARCHAR(50);
+ALTER TABLE outbox ADD COLUMN author_id UUID;
+ALTER TABLE outbox ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE outbox
+SET author_type = COALESCE(payload->>'authorType', payload->'bid'->>'authorType'),
+    author_id = COALESCE(payload->>'authorId', payload->'bid'->>'authorId')::uuid
+WHERE bid_id IS NOT NULL;
+
+UPDATE outbox
+SET public = TRUE
+WHERE bid_id IS NULL AND (payload->>'status' = 'Published' OR type = 'TenderPublished');
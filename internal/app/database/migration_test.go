@@ -0,0 +1,206 @@
+package database_test
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openSchema connects to the database from TEST_POSTGRES_DSN and points a
+// single connection at a fresh schema, which is dropped when the test ends.
+func openSchema(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+
+	schema := "migration_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err = db.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		db.Close()
+	})
+
+	if _, err = db.Exec("SET search_path TO " + schema + ", public"); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func apply(db *sql.DB, version int) error {
+	files, err := filepath.Glob(fmt.Sprintf("migration/%06d_*.up.sql", version))
+	if err != nil || len(files) != 1 {
+		return fmt.Errorf("migration %d not found", version)
+	}
+
+	query, err := os.ReadFile(files[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(string(query))
+	return err
+}
+
+func migrate(t *testing.T, db *sql.DB, versions ...int) {
+	t.Helper()
+
+	for _, version := range versions {
+		if err := apply(db, version); err != nil {
+			t.Fatalf("migration %d: %v", version, err)
+		}
+	}
+}
+
+type snapshot struct {
+	version int
+	name    string
+	status  string
+}
+
+func history(t *testing.T, db *sql.DB, tenderId string) []snapshot {
+	t.Helper()
+
+	rows, err := db.Query("SELECT version, name, status FROM tender_diff WHERE id = $1 ORDER BY version", tenderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var snapshots []snapshot
+	for rows.Next() {
+		var s snapshot
+		if err = rows.Scan(&s.version, &s.name, &s.status); err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots
+}
+
+func diff(t *testing.T, db *sql.DB, tenderId string, name string, status string, version int) {
+	t.Helper()
+
+	exec(t, db, "INSERT INTO tender_diff (id, name, description, status, service_type, version, organization_id, creator_username, created_at) SELECT id, $2::text, description, $3::tender_status, service_type, $4::int, organization_id, creator_username, created_at FROM tender WHERE id = $1", tenderId, name, status, version)
+}
+
+func exec(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepairVersionHistory(t *testing.T) {
+	db := openSchema(t)
+	migrate(t, db, 1, 2, 3, 4, 5)
+
+	var organizationId string
+	err := db.QueryRow("INSERT INTO organization (name) VALUES ('org') RETURNING id").Scan(&organizationId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec("INSERT INTO employee (username) VALUES ('user')"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The statements below write history the way the baseline handlers did.
+	addTender := func() string {
+		var id string
+		err := db.QueryRow("INSERT INTO tender (name, description, status, service_type, version, organization_id, creator_username) VALUES ('v1', '', 'Created', 'Delivery', 1, $1, 'user') RETURNING id", organizationId).Scan(&id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		diff(t, db, id, "v1", "Created", 1)
+		return id
+	}
+
+	// Add, then Patch, then PutStatus.
+	updated := addTender()
+	exec(t, db, "UPDATE tender SET name = 'v2', version = 2 WHERE id = $1", updated)
+	diff(t, db, updated, "v2", "Created", 2)
+	exec(t, db, "UPDATE tender SET status = 'Published', version = 3 WHERE id = $1", updated)
+	diff(t, db, updated, "v2", "Created", 3)
+
+	// Add, then Patch, then Rollback to version 1.
+	rolledBack := addTender()
+	exec(t, db, "UPDATE tender SET name = 'v2', version = 2 WHERE id = $1", rolledBack)
+	diff(t, db, rolledBack, "v2", "Created", 2)
+	exec(t, db, "UPDATE tender SET name = 'v1', version = 3 WHERE id = $1", rolledBack)
+	diff(t, db, rolledBack, "v1", "Created", 2)
+
+	migrate(t, db, 6)
+
+	want := []snapshot{{1, "v1", "Created"}, {2, "v2", "Created"}, {3, "v2", "Published"}}
+	if got := history(t, db, updated); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+
+	want = []snapshot{{3, "v1", "Created"}}
+	if got := history(t, db, rolledBack); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+
+	var legacy int
+	if err = db.QueryRow("SELECT COUNT(*) FROM tender_diff_legacy WHERE id = $1", rolledBack).Scan(&legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy != 3 {
+		t.Fatalf("legacy rows = %d, want 3", legacy)
+	}
+}
+
+func TestUniqueResponsibleReportsDuplicates(t *testing.T) {
+	db := openSchema(t)
+	for version := 1; version <= 14; version++ {
+		migrate(t, db, version)
+	}
+
+	var first, second string
+	if err := db.QueryRow("INSERT INTO organization (name) VALUES ('first') RETURNING id").Scan(&first); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("INSERT INTO organization (name) VALUES ('second') RETURNING id").Scan(&second); err != nil {
+		t.Fatal(err)
+	}
+	exec(t, db, "INSERT INTO employee (username) VALUES ('twice'), ('both')")
+	exec(t, db, "INSERT INTO organization_responsible (organization_id, user_id) SELECT $1::uuid, id FROM employee WHERE username = 'twice'", first)
+	exec(t, db, "INSERT INTO organization_responsible (organization_id, user_id) SELECT $1::uuid, id FROM employee WHERE username = 'twice'", first)
+	exec(t, db, "INSERT INTO organization_responsible (organization_id, user_id) SELECT $1::uuid, id FROM employee WHERE username = 'both'", first)
+	exec(t, db, "INSERT INTO organization_responsible (organization_id, user_id) SELECT $1::uuid, id FROM employee WHERE username = 'both'", second)
+
+	err := apply(db, 15)
+	if err == nil || !strings.Contains(err.Error(), "more than one organization: both") {
+		t.Fatalf("migration err = %v, want the employee in two organizations reported", err)
+	}
+
+	exec(t, db, "DELETE FROM organization_responsible WHERE organization_id = $1", second)
+	migrate(t, db, 15)
+
+	var count int
+	if err = db.QueryRow("SELECT COUNT(*) FROM organization_responsible").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("responsibles = %d, want 2", count)
+	}
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"slices"
+)
+
+type AccessRepository struct {
+	*Store
+}
+
+func NewAccessRepository(store *Store) *AccessRepository {
+	return &AccessRepository{
+		Store: store,
+	}
+}
+
+func (r *AccessRepository) UserExists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+
+	err := r.read(ctx, func(st *state) error {
+		_, exists = st.employeeByUsername(username)
+		return nil
+	})
+
+	return exists, err
+}
+
+func (r *AccessRepository) UserId(ctx context.Context, username string) (string, error) {
+	var userId string
+
+	err := r.read(ctx, func(st *state) error {
+		e, ok := st.employeeByUsername(username)
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		userId = e.id
+
+		return nil
+	})
+
+	return userId, err
+}
+
+func (r *AccessRepository) Role(ctx context.Context, username string, organizationId string) (access.Role, error) {
+	var role access.Role
+
+	err := r.read(ctx, func(st *state) error {
+		e, ok := st.employeeByUsername(username)
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		if role, ok = st.role(organizationId, e.id); !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return role, err
+}
+
+func (r *AccessRepository) CountMembers(ctx context.Context, organizationId string, roles []access.Role) (int, error) {
+	var count int
+
+	err := r.read(ctx, func(st *state) error {
+		for _, resp := range organizationTable.of(st).responsibles {
+			if resp.organizationId == organizationId && slices.Contains(roles, resp.role) {
+				count++
+			}
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+func (r *AccessRepository) Organizations(ctx context.Context, username string, roles []access.Role) ([]string, error) {
+	var organizationIds []string
+
+	err := r.read(ctx, func(st *state) error {
+		e, ok := st.employeeByUsername(username)
+		if !ok {
+			return nil
+		}
+
+		for _, resp := range organizationTable.of(st).responsibles {
+			if resp.userId == e.id && slices.Contains(roles, resp.role) {
+				organizationIds = append(organizationIds, resp.organizationId)
+			}
+		}
+
+		return nil
+	})
+
+	return organizationIds, err
+}
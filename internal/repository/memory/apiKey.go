@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"time"
+)
+
+type apiKeyState struct {
+	apiKeys map[string]apikey.Key
+}
+
+var apiKeyTable = register(func() *apiKeyState {
+	return &apiKeyState{
+		apiKeys: make(map[string]apikey.Key),
+	}
+})
+
+func (t *apiKeyState) clone() table {
+	return &apiKeyState{
+		apiKeys: maps.Clone(t.apiKeys),
+	}
+}
+
+type ApiKeyRepository struct {
+	*Store
+}
+
+func NewApiKeyRepository(store *Store) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Store: store,
+	}
+}
+
+func (r *ApiKeyRepository) CreateKey(ctx context.Context, k apikey.Key) (apikey.Key, error) {
+	err := r.write(ctx, func(st *state) error {
+		ks := apiKeyTable.of(st)
+
+		if _, ok := organizationTable.of(st).organizations[k.OrganizationId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := employeeTable.of(st).employees[k.CreatorId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		for _, existing := range ks.apiKeys {
+			if existing.KeyHash == k.KeyHash {
+				return repository.ErrDuplicate
+			}
+		}
+
+		k.Id = uuid.New()
+		k.CreatedAt = now()
+		ks.apiKeys[k.Id.String()] = k
+
+		return nil
+	})
+
+	return k, err
+}
+
+func (r *ApiKeyRepository) GetKey(ctx context.Context, keyId string) (apikey.Key, error) {
+	var k apikey.Key
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if k, ok = apiKeyTable.of(st).apiKeys[keyId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return k, err
+}
+
+func (r *ApiKeyRepository) GetKeyByHash(ctx context.Context, keyHash string) (apikey.Key, error) {
+	var k apikey.Key
+
+	err := r.read(ctx, func(st *state) error {
+		for _, existing := range apiKeyTable.of(st).apiKeys {
+			if existing.KeyHash == keyHash {
+				k = existing
+				return nil
+			}
+		}
+
+		return repository.ErrNotFound
+	})
+
+	return k, err
+}
+
+func (r *ApiKeyRepository) ListKeys(ctx context.Context, organizationId string, limit int, offset int) ([]apikey.Key, error) {
+	var keys []apikey.Key
+
+	err := r.read(ctx, func(st *state) error {
+		for _, k := range apiKeyTable.of(st).apiKeys {
+			if k.OrganizationId.String() == organizationId {
+				keys = append(keys, k)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(keys, func(a, b apikey.Key) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return paginate(keys, limit, offset), nil
+}
+
+func (r *ApiKeyRepository) RevokeKey(ctx context.Context, keyId string, at time.Time) error {
+	return r.update(ctx, keyId, func(k *apikey.Key) {
+		k.RevokedAt = &at
+	})
+}
+
+func (r *ApiKeyRepository) TouchKey(ctx context.Context, keyId string, at time.Time) error {
+	return r.update(ctx, keyId, func(k *apikey.Key) {
+		k.LastUsedAt = &at
+	})
+}
+
+func (r *ApiKeyRepository) update(ctx context.Context, keyId string, fn func(k *apikey.Key)) error {
+	return r.write(ctx, func(st *state) error {
+		ks := apiKeyTable.of(st)
+
+		k, ok := ks.apiKeys[keyId]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		fn(&k)
+		ks.apiKeys[keyId] = k
+
+		return nil
+	})
+}
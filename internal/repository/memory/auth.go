@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"github.com/google/uuid"
+	"maps"
+	"time"
+)
+
+type authState struct {
+	refreshTokens map[string]auth.RefreshToken
+}
+
+var authTable = register(func() *authState {
+	return &authState{
+		refreshTokens: make(map[string]auth.RefreshToken),
+	}
+})
+
+func (t *authState) clone() table {
+	return &authState{
+		refreshTokens: maps.Clone(t.refreshTokens),
+	}
+}
+
+type AuthRepository struct {
+	*Store
+}
+
+func NewAuthRepository(store *Store) *AuthRepository {
+	return &AuthRepository{
+		Store: store,
+	}
+}
+
+func (r *AuthRepository) GetAccount(ctx context.Context, username string) (auth.Account, error) {
+	var account auth.Account
+
+	err := r.read(ctx, func(st *state) error {
+		e, ok := st.employeeByUsername(username)
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		account = auth.Account{UserId: e.id, Username: e.username, PasswordHash: e.passwordHash}
+
+		return nil
+	})
+
+	return account, err
+}
+
+func (r *AuthRepository) GetAccountById(ctx context.Context, userId string) (auth.Account, error) {
+	var account auth.Account
+
+	err := r.read(ctx, func(st *state) error {
+		e, ok := employeeTable.of(st).employees[userId]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		account = auth.Account{UserId: e.id, Username: e.username, PasswordHash: e.passwordHash}
+
+		return nil
+	})
+
+	return account, err
+}
+
+func (r *AuthRepository) SetPasswordHash(ctx context.Context, userId string, passwordHash string) error {
+	return r.write(ctx, func(st *state) error {
+		emps := employeeTable.of(st)
+
+		e, ok := emps.employees[userId]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		e.passwordHash = passwordHash
+		emps.employees[userId] = e
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token auth.RefreshToken) error {
+	return r.write(ctx, func(st *state) error {
+		as := authTable.of(st)
+
+		if _, ok := employeeTable.of(st).employees[token.UserId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := as.refreshTokens[token.TokenHash]; ok {
+			return repository.ErrDuplicate
+		}
+
+		token.Id = uuid.New()
+		as.refreshTokens[token.TokenHash] = token
+
+		return nil
+	})
+}
+
+func (r *AuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (auth.RefreshToken, error) {
+	var token auth.RefreshToken
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if token, ok = authTable.of(st).refreshTokens[tokenHash]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return token, err
+}
+
+func (r *AuthRepository) RevokeRefreshToken(ctx context.Context, tokenId string, at time.Time) error {
+	return r.write(ctx, func(st *state) error {
+		as := authTable.of(st)
+
+		for hash, token := range as.refreshTokens {
+			if token.Id.String() == tokenId && token.RevokedAt == nil {
+				token.RevokedAt = &at
+				as.refreshTokens[hash] = token
+				return nil
+			}
+		}
+
+		return repository.ErrNotFound
+	})
+}
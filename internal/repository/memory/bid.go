@@ -0,0 +1,386 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+)
+
+type decisionKey struct {
+	bidId  string
+	userId string
+}
+
+type review struct {
+	bid.BidReview
+	bidId  string
+	userId string
+}
+
+type bidState struct {
+	bids      map[string]bid.Bid
+	bidDiffs  []bid.Bid
+	decisions map[decisionKey]bid.BidDecision
+	reviews   []review
+}
+
+var bidTable = register(func() *bidState {
+	return &bidState{
+		bids:      make(map[string]bid.Bid),
+		decisions: make(map[decisionKey]bid.BidDecision),
+	}
+})
+
+func (t *bidState) clone() table {
+	return &bidState{
+		bids:      maps.Clone(t.bids),
+		bidDiffs:  slices.Clone(t.bidDiffs),
+		decisions: maps.Clone(t.decisions),
+		reviews:   slices.Clone(t.reviews),
+	}
+}
+
+type BidRepository struct {
+	*Store
+}
+
+func NewBidRepository(store *Store) *BidRepository {
+	return &BidRepository{
+		Store: store,
+	}
+}
+
+func (r *BidRepository) Create(ctx context.Context, b bid.Bid) (bid.Bid, error) {
+	err := r.write(ctx, func(st *state) error {
+		if _, ok := tenderTable.of(st).tenders[b.TenderId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if !st.authorExists(b.AuthorType, b.AuthorId) {
+			return repository.ErrInvalidReference
+		}
+
+		b.Id = uuid.New()
+		b.CreatedAt = now()
+		bidTable.of(st).bids[b.Id.String()] = b
+
+		return nil
+	})
+
+	return b, err
+}
+
+func (r *BidRepository) Get(ctx context.Context, bidId string) (bid.Bid, error) {
+	var b bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if b, ok = bidTable.of(st).bids[bidId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return b, err
+}
+
+func (r *BidRepository) GetVersion(ctx context.Context, bidId string, version int) (bid.Bid, error) {
+	var b bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		for _, diff := range bidTable.of(st).bidDiffs {
+			if diff.Id.String() == bidId && diff.Version == version {
+				b = diff
+				return nil
+			}
+		}
+
+		return repository.ErrNotFound
+	})
+
+	return b, err
+}
+
+func (r *BidRepository) ListVersions(ctx context.Context, bidId string, limit int, offset int) ([]bid.Bid, error) {
+	var versions []bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		for _, diff := range bidTable.of(st).bidDiffs {
+			if diff.Id.String() == bidId {
+				versions = append(versions, diff)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(versions, func(a, b bid.Bid) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+
+	return paginate(versions, limit, offset), nil
+}
+
+func (r *BidRepository) Update(ctx context.Context, b bid.Bid) error {
+	return r.write(ctx, func(st *state) error {
+		bs := bidTable.of(st)
+
+		if _, ok := bs.bids[b.Id.String()]; !ok {
+			return repository.ErrNotFound
+		}
+
+		if !st.authorExists(b.AuthorType, b.AuthorId) {
+			return repository.ErrInvalidReference
+		}
+
+		bs.bids[b.Id.String()] = b
+
+		return nil
+	})
+}
+
+func (r *BidRepository) InsertDiff(ctx context.Context, b bid.Bid) error {
+	return r.write(ctx, func(st *state) error {
+		bs := bidTable.of(st)
+
+		if _, ok := bs.bids[b.Id.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		for _, diff := range bs.bidDiffs {
+			if diff.Id == b.Id && diff.Version == b.Version {
+				return repository.ErrDuplicate
+			}
+		}
+
+		bs.bidDiffs = append(bs.bidDiffs, b)
+
+		return nil
+	})
+}
+
+func (r *BidRepository) ListByAuthor(ctx context.Context, authors access.Authorship, request page.Request) ([]bid.Bid, error) {
+	bids, err := r.byAuthor(ctx, authors)
+	return paginatePage(bids, request, bidKey), err
+}
+
+func (r *BidRepository) CountByAuthor(ctx context.Context, authors access.Authorship) (int, error) {
+	bids, err := r.byAuthor(ctx, authors)
+	return len(bids), err
+}
+
+func (r *BidRepository) ListByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool, request page.Request) ([]bid.Bid, error) {
+	bids, err := r.byTender(ctx, tenderId, authors, withPublished)
+	return paginatePage(bids, request, bidKey), err
+}
+
+func (r *BidRepository) CountByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool) (int, error) {
+	bids, err := r.byTender(ctx, tenderId, authors, withPublished)
+	return len(bids), err
+}
+
+func (r *BidRepository) ListByTenderStatus(ctx context.Context, tenderId string, statuses ...bid.BidStatus) ([]bid.Bid, error) {
+	var bids []bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		for _, b := range bidTable.of(st).bids {
+			if b.TenderId.String() == tenderId && slices.Contains(statuses, b.Status) {
+				bids = append(bids, b)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortBids(bids), nil
+}
+
+func (r *BidRepository) HasAuthoredBids(ctx context.Context, tenderId string, authors access.Authorship) (bool, error) {
+	var exists bool
+
+	err := r.read(ctx, func(st *state) error {
+		for _, b := range bidTable.of(st).bids {
+			if b.TenderId.String() == tenderId && authoredBy(b, authors) {
+				exists = true
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return exists, err
+}
+
+func (r *BidRepository) SaveDecision(ctx context.Context, bidId string, userId string, decision bid.BidDecision) error {
+	return r.write(ctx, func(st *state) error {
+		bs := bidTable.of(st)
+
+		if _, ok := bs.bids[bidId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := employeeTable.of(st).employees[userId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		bs.decisions[decisionKey{bidId: bidId, userId: userId}] = decision
+
+		return nil
+	})
+}
+
+func (r *BidRepository) CountDecisions(ctx context.Context, bidId string, decision bid.BidDecision) (int, error) {
+	var count int
+
+	err := r.read(ctx, func(st *state) error {
+		for key, d := range bidTable.of(st).decisions {
+			if key.bidId == bidId && d == decision {
+				count++
+			}
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+func (r *BidRepository) CreateReview(ctx context.Context, bidId string, userId string, description string) error {
+	return r.write(ctx, func(st *state) error {
+		bs := bidTable.of(st)
+
+		if _, ok := bs.bids[bidId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := employeeTable.of(st).employees[userId]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		bs.reviews = append(bs.reviews, review{
+			BidReview: bid.BidReview{Id: uuid.New(), Description: description, CreatedAt: now()},
+			bidId:     bidId,
+			userId:    userId,
+		})
+
+		return nil
+	})
+}
+
+func (r *BidRepository) ListReviews(ctx context.Context, authors access.Authorship, limit int, offset int) ([]bid.BidReview, error) {
+	var reviews []bid.BidReview
+
+	err := r.read(ctx, func(st *state) error {
+		bs := bidTable.of(st)
+
+		for _, rv := range bs.reviews {
+			if authoredBy(bs.bids[rv.bidId], authors) {
+				reviews = append(reviews, rv.BidReview)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(reviews, func(a, b bid.BidReview) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return paginate(reviews, limit, offset), nil
+}
+
+func (s *state) authorExists(authorType bid.BidAuthor, authorId string) bool {
+	switch authorType {
+	case bid.BidAuthorUser:
+		_, ok := employeeTable.of(s).employees[authorId]
+		return ok
+	case bid.BidAuthorOrganization:
+		_, ok := organizationTable.of(s).organizations[authorId]
+		return ok
+	}
+
+	return false
+}
+
+func authoredBy(b bid.Bid, authors access.Authorship) bool {
+	switch b.AuthorType {
+	case bid.BidAuthorUser:
+		return authors.UserId != "" && b.AuthorId == authors.UserId
+	case bid.BidAuthorOrganization:
+		return slices.Contains(authors.OrganizationIds, b.AuthorId)
+	}
+
+	return false
+}
+
+func (r *BidRepository) byAuthor(ctx context.Context, authors access.Authorship) ([]bid.Bid, error) {
+	var bids []bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		for _, b := range bidTable.of(st).bids {
+			if authoredBy(b, authors) {
+				bids = append(bids, b)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortBids(bids), nil
+}
+
+func (r *BidRepository) byTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool) ([]bid.Bid, error) {
+	var bids []bid.Bid
+
+	err := r.read(ctx, func(st *state) error {
+		for _, b := range bidTable.of(st).bids {
+			if b.TenderId.String() != tenderId {
+				continue
+			}
+
+			published := slices.Contains([]bid.BidStatus{bid.BidStatusPublished, bid.BidStatusApproved, bid.BidStatusRejected, bid.BidStatusNotSelected}, b.Status)
+			if authoredBy(b, authors) || (withPublished && published) {
+				bids = append(bids, b)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sortBids(bids), nil
+}
+
+func bidKey(b bid.Bid) page.Key {
+	return page.Key{Name: b.Name, Id: b.Id}
+}
+
+func sortBids(bids []bid.Bid) []bid.Bid {
+	slices.SortFunc(bids, func(a, b bid.Bid) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return bids
+}
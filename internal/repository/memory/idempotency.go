@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"maps"
+	"time"
+)
+
+type idempotencyKey struct {
+	scope string
+	key   string
+}
+
+type idempotencyState struct {
+	idempotency map[idempotencyKey]idempotency.Record
+}
+
+var idempotencyTable = register(func() *idempotencyState {
+	return &idempotencyState{
+		idempotency: make(map[idempotencyKey]idempotency.Record),
+	}
+})
+
+func (t *idempotencyState) clone() table {
+	return &idempotencyState{
+		idempotency: maps.Clone(t.idempotency),
+	}
+}
+
+type IdempotencyRepository struct {
+	*Store
+}
+
+func NewIdempotencyRepository(store *Store) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		Store: store,
+	}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record idempotency.Record, now time.Time) (bool, error) {
+	reserved := false
+
+	err := r.write(ctx, func(st *state) error {
+		is := idempotencyTable.of(st)
+
+		k := idempotencyKey{scope: record.Scope, key: record.Key}
+		if existing, ok := is.idempotency[k]; ok && existing.ExpiresAt.After(now) {
+			return nil
+		}
+
+		record.Response = nil
+		is.idempotency[k] = record
+		reserved = true
+
+		return nil
+	})
+
+	return reserved, err
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, scope string, key string) (idempotency.Record, error) {
+	var record idempotency.Record
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		record, ok = idempotencyTable.of(st).idempotency[idempotencyKey{scope: scope, key: key}]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return record, err
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope string, key string, response idempotency.Response) error {
+	return r.write(ctx, func(st *state) error {
+		is := idempotencyTable.of(st)
+
+		k := idempotencyKey{scope: scope, key: key}
+		record, ok := is.idempotency[k]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		record.Response = &response
+		is.idempotency[k] = record
+
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, scope string, key string) error {
+	return r.write(ctx, func(st *state) error {
+		delete(idempotencyTable.of(st).idempotency, idempotencyKey{scope: scope, key: key})
+		return nil
+	})
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+
+	err := r.write(ctx, func(st *state) error {
+		is := idempotencyTable.of(st)
+
+		for k, record := range is.idempotency {
+			if !record.ExpiresAt.After(now) {
+				delete(is.idempotency, k)
+				deleted++
+			}
+		}
+
+		return nil
+	})
+
+	return deleted, err
+}
@@ -0,0 +1,484 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"time"
+)
+
+type employee struct {
+	id           string
+	username     string
+	firstName    string
+	lastName     string
+	passwordHash string
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+type responsible struct {
+	organizationId string
+	userId         string
+	role           access.Role
+}
+
+type employeeState struct {
+	employees map[string]employee
+}
+
+var employeeTable = register(func() *employeeState {
+	return &employeeState{
+		employees: make(map[string]employee),
+	}
+})
+
+func (t *employeeState) clone() table {
+	return &employeeState{
+		employees: maps.Clone(t.employees),
+	}
+}
+
+type organizationState struct {
+	organizations map[string]organization.Organization
+	responsibles  []responsible
+}
+
+var organizationTable = register(func() *organizationState {
+	return &organizationState{
+		organizations: make(map[string]organization.Organization),
+	}
+})
+
+func (t *organizationState) clone() table {
+	return &organizationState{
+		organizations: maps.Clone(t.organizations),
+		responsibles:  slices.Clone(t.responsibles),
+	}
+}
+
+type OrganizationRepository struct {
+	*Store
+}
+
+func NewOrganizationRepository(store *Store) *OrganizationRepository {
+	return &OrganizationRepository{
+		Store: store,
+	}
+}
+
+func (r *OrganizationRepository) CreateEmployee(ctx context.Context, e organization.Employee) (organization.Employee, error) {
+	err := r.write(ctx, func(st *state) error {
+		if _, ok := st.employeeByUsername(e.Username); ok {
+			return repository.ErrDuplicate
+		}
+
+		e.Id = uuid.New()
+		e.CreatedAt = now()
+		e.UpdatedAt = e.CreatedAt
+		employeeTable.of(st).employees[e.Id.String()] = employee{
+			id:           e.Id.String(),
+			username:     e.Username,
+			firstName:    e.FirstName,
+			lastName:     e.LastName,
+			passwordHash: e.PasswordHash,
+			createdAt:    e.CreatedAt,
+			updatedAt:    e.UpdatedAt,
+		}
+
+		return nil
+	})
+
+	e.PasswordHash = ""
+
+	return e, err
+}
+
+func (r *OrganizationRepository) GetEmployee(ctx context.Context, employeeId string) (organization.Employee, error) {
+	var e organization.Employee
+
+	err := r.read(ctx, func(st *state) error {
+		found, ok := employeeTable.of(st).employees[employeeId]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		e = found.model()
+
+		return nil
+	})
+
+	return e, err
+}
+
+func (r *OrganizationRepository) ListEmployees(ctx context.Context, limit int, offset int) ([]organization.Employee, error) {
+	var employees []organization.Employee
+
+	err := r.read(ctx, func(st *state) error {
+		for _, e := range employeeTable.of(st).employees {
+			employees = append(employees, e.model())
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(employees, func(a, b organization.Employee) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+
+	return paginate(employees, limit, offset), err
+}
+
+func (r *OrganizationRepository) UpdateEmployee(ctx context.Context, e organization.Employee) (organization.Employee, error) {
+	var updated organization.Employee
+
+	err := r.write(ctx, func(st *state) error {
+		emps := employeeTable.of(st)
+
+		stored, ok := emps.employees[e.Id.String()]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		stored.firstName = e.FirstName
+		stored.lastName = e.LastName
+		stored.updatedAt = now()
+		emps.employees[stored.id] = stored
+		updated = stored.model()
+
+		return nil
+	})
+
+	return updated, err
+}
+
+func (r *OrganizationRepository) DeleteEmployee(ctx context.Context, employeeId string) error {
+	return r.write(ctx, func(st *state) error {
+		emps := employeeTable.of(st)
+		orgs := organizationTable.of(st)
+		bs := bidTable.of(st)
+
+		e, ok := emps.employees[employeeId]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		for _, t := range tenderTable.of(st).tenders {
+			if t.CreatorUsername == e.username {
+				return repository.ErrInvalidReference
+			}
+		}
+
+		delete(emps.employees, employeeId)
+		orgs.responsibles = slices.DeleteFunc(orgs.responsibles, func(resp responsible) bool {
+			return resp.userId == employeeId
+		})
+		bs.reviews = slices.DeleteFunc(bs.reviews, func(rv review) bool {
+			return rv.userId == employeeId
+		})
+		maps.DeleteFunc(bs.decisions, func(key decisionKey, _ bid.BidDecision) bool {
+			return key.userId == employeeId
+		})
+		maps.DeleteFunc(authTable.of(st).refreshTokens, func(_ string, token auth.RefreshToken) bool {
+			return token.UserId == employeeId
+		})
+		maps.DeleteFunc(apiKeyTable.of(st).apiKeys, func(_ string, k apikey.Key) bool {
+			return k.CreatorId == employeeId
+		})
+
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) CreateOrganization(ctx context.Context, o organization.Organization) (organization.Organization, error) {
+	err := r.write(ctx, func(st *state) error {
+		o.Id = uuid.New()
+		o.CreatedAt = now()
+		o.UpdatedAt = o.CreatedAt
+		organizationTable.of(st).organizations[o.Id.String()] = o
+
+		return nil
+	})
+
+	return o, err
+}
+
+func (r *OrganizationRepository) GetOrganization(ctx context.Context, organizationId string) (organization.Organization, error) {
+	var o organization.Organization
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if o, ok = organizationTable.of(st).organizations[organizationId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return o, err
+}
+
+func (r *OrganizationRepository) ListOrganizations(ctx context.Context, limit int, offset int) ([]organization.Organization, error) {
+	var organizations []organization.Organization
+
+	err := r.read(ctx, func(st *state) error {
+		for _, o := range organizationTable.of(st).organizations {
+			organizations = append(organizations, o)
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(organizations, func(a, b organization.Organization) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return paginate(organizations, limit, offset), err
+}
+
+func (r *OrganizationRepository) UpdateOrganization(ctx context.Context, o organization.Organization) (organization.Organization, error) {
+	err := r.write(ctx, func(st *state) error {
+		orgs := organizationTable.of(st)
+
+		stored, ok := orgs.organizations[o.Id.String()]
+		if !ok {
+			return repository.ErrNotFound
+		}
+
+		o.CreatedAt = stored.CreatedAt
+		o.UpdatedAt = now()
+		orgs.organizations[o.Id.String()] = o
+
+		return nil
+	})
+
+	return o, err
+}
+
+func (r *OrganizationRepository) DeleteOrganization(ctx context.Context, organizationId string) error {
+	return r.write(ctx, func(st *state) error {
+		orgs := organizationTable.of(st)
+
+		if _, ok := orgs.organizations[organizationId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(orgs.organizations, organizationId)
+		orgs.responsibles = slices.DeleteFunc(orgs.responsibles, func(resp responsible) bool {
+			return resp.organizationId == organizationId
+		})
+		maps.DeleteFunc(webhookTable.of(st).subscriptions, func(_ string, s webhook.Subscription) bool {
+			return s.OrganizationId.String() == organizationId
+		})
+		maps.DeleteFunc(apiKeyTable.of(st).apiKeys, func(_ string, k apikey.Key) bool {
+			return k.OrganizationId.String() == organizationId
+		})
+
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) HasTenders(ctx context.Context, organizationId string) (bool, error) {
+	var exists bool
+
+	err := r.read(ctx, func(st *state) error {
+		for _, t := range tenderTable.of(st).tenders {
+			if t.OrganizationId.String() == organizationId {
+				exists = true
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return exists, err
+}
+
+func (r *OrganizationRepository) CreateResponsible(ctx context.Context, resp organization.Responsible) error {
+	return r.write(ctx, func(st *state) error {
+		orgs := organizationTable.of(st)
+
+		if _, ok := orgs.organizations[resp.OrganizationId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := employeeTable.of(st).employees[resp.UserId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		for _, existing := range orgs.responsibles {
+			if existing.userId == resp.UserId.String() {
+				return repository.ErrDuplicate
+			}
+		}
+
+		orgs.responsibles = append(orgs.responsibles, responsible{
+			organizationId: resp.OrganizationId.String(),
+			userId:         resp.UserId.String(),
+			role:           resp.Role,
+		})
+
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) GetResponsible(ctx context.Context, userId string) (organization.Responsible, error) {
+	var found organization.Responsible
+
+	err := r.read(ctx, func(st *state) error {
+		for _, resp := range organizationTable.of(st).responsibles {
+			if resp.userId == userId {
+				found = st.responsibleModel(resp)
+				return nil
+			}
+		}
+
+		return repository.ErrNotFound
+	})
+
+	return found, err
+}
+
+func (r *OrganizationRepository) ListResponsibles(ctx context.Context, organizationId string, limit int, offset int) ([]organization.Responsible, error) {
+	var responsibles []organization.Responsible
+
+	err := r.read(ctx, func(st *state) error {
+		for _, resp := range organizationTable.of(st).responsibles {
+			if resp.organizationId == organizationId {
+				responsibles = append(responsibles, st.responsibleModel(resp))
+			}
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(responsibles, func(a, b organization.Responsible) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+
+	return paginate(responsibles, limit, offset), err
+}
+
+func (r *OrganizationRepository) DeleteResponsible(ctx context.Context, organizationId string, userId string) error {
+	return r.write(ctx, func(st *state) error {
+		orgs := organizationTable.of(st)
+
+		count := len(orgs.responsibles)
+		orgs.responsibles = slices.DeleteFunc(orgs.responsibles, func(resp responsible) bool {
+			return resp.organizationId == organizationId && resp.userId == userId
+		})
+
+		if len(orgs.responsibles) == count {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) CountResponsibles(ctx context.Context, organizationId string, role access.Role) (int, error) {
+	var count int
+
+	err := r.read(ctx, func(st *state) error {
+		for _, resp := range organizationTable.of(st).responsibles {
+			if resp.organizationId == organizationId && resp.role == role {
+				count++
+			}
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+func (e employee) model() organization.Employee {
+	return organization.Employee{
+		Id:        uuid.MustParse(e.id),
+		Username:  e.username,
+		FirstName: e.firstName,
+		LastName:  e.lastName,
+		CreatedAt: e.createdAt,
+		UpdatedAt: e.updatedAt,
+	}
+}
+
+func (s *state) responsibleModel(resp responsible) organization.Responsible {
+	return organization.Responsible{
+		OrganizationId: uuid.MustParse(resp.organizationId),
+		UserId:         uuid.MustParse(resp.userId),
+		Username:       employeeTable.of(s).employees[resp.userId].username,
+		Role:           resp.role,
+	}
+}
+
+func (s *Store) AddEmployee(username string) string {
+	id := uuid.NewString()
+
+	s.write(context.Background(), func(st *state) error {
+		employeeTable.of(st).employees[id] = employee{id: id, username: username, createdAt: now(), updatedAt: now()}
+		return nil
+	})
+
+	return id
+}
+
+func (s *Store) AddOrganization(name string) string {
+	id := uuid.NewString()
+
+	s.write(context.Background(), func(st *state) error {
+		organizationTable.of(st).organizations[id] = organization.Organization{
+			Id:        uuid.MustParse(id),
+			Name:      name,
+			Type:      organization.OrganizationTypeLLC,
+			CreatedAt: now(),
+			UpdatedAt: now(),
+		}
+		return nil
+	})
+
+	return id
+}
+
+func (s *Store) AddResponsible(organizationId string, userId string) {
+	s.AddMember(organizationId, userId, access.RoleOwner)
+}
+
+func (s *Store) AddMember(organizationId string, userId string, role access.Role) {
+	s.write(context.Background(), func(st *state) error {
+		orgs := organizationTable.of(st)
+
+		orgs.responsibles = append(orgs.responsibles, responsible{organizationId: organizationId, userId: userId, role: role})
+		return nil
+	})
+}
+
+func (s *state) employeeByUsername(username string) (employee, bool) {
+	for _, e := range employeeTable.of(s).employees {
+		if e.username == username {
+			return e, true
+		}
+	}
+
+	return employee{}, false
+}
+
+func (s *state) role(organizationId string, userId string) (access.Role, bool) {
+	for _, resp := range organizationTable.of(s).responsibles {
+		if resp.organizationId == organizationId && resp.userId == userId {
+			return resp.role, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"slices"
+	"time"
+)
+
+type outboxEntry struct {
+	event.Event
+	publishedAt *time.Time
+}
+
+type outboxState struct {
+	outbox   []outboxEntry
+	sequence int64
+}
+
+var outboxTable = register(func() *outboxState {
+	return &outboxState{}
+})
+
+func (t *outboxState) clone() table {
+	return &outboxState{
+		outbox:   slices.Clone(t.outbox),
+		sequence: t.sequence,
+	}
+}
+
+type OutboxRepository struct {
+	*Store
+}
+
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{
+		Store: store,
+	}
+}
+
+func (r *OutboxRepository) Append(ctx context.Context, e event.Event) error {
+	return r.write(ctx, func(st *state) error {
+		obs := outboxTable.of(st)
+
+		e.Id = int64(len(obs.outbox) + 1)
+		e.CreatedAt = now()
+		obs.outbox = append(obs.outbox, outboxEntry{Event: e})
+
+		return nil
+	})
+}
+
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]event.Event, error) {
+	var events []event.Event
+
+	err := r.read(ctx, func(st *state) error {
+		for _, entry := range outboxTable.of(st).outbox {
+			if entry.publishedAt == nil && len(events) < limit {
+				events = append(events, entry.Event)
+			}
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+func (r *OutboxRepository) ListAfter(ctx context.Context, afterSequence int64, limit int) ([]event.Event, error) {
+	var events []event.Event
+
+	err := r.read(ctx, func(st *state) error {
+		for _, entry := range outboxTable.of(st).outbox {
+			if entry.publishedAt != nil && entry.Sequence > afterSequence {
+				events = append(events, entry.Event)
+			}
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(events, func(a, b event.Event) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+
+	return paginate(events, limit, 0), err
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64, at time.Time) ([]int64, error) {
+	sequences := make([]int64, 0, len(ids))
+
+	err := r.write(ctx, func(st *state) error {
+		obs := outboxTable.of(st)
+
+		for _, id := range ids {
+			i := slices.IndexFunc(obs.outbox, func(entry outboxEntry) bool { return entry.Id == id })
+			if i < 0 {
+				return repository.ErrNotFound
+			}
+
+			obs.sequence++
+			obs.outbox[i].publishedAt = &at
+			obs.outbox[i].Sequence = obs.sequence
+			sequences = append(sequences, obs.sequence)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sequences, nil
+}
+
+// TryLock always succeeds: the store serializes writers itself.
+func (r *OutboxRepository) TryLock(ctx context.Context, key string) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
+// table holds the state of one aggregate. Repositories register their tables
+// with register, so the store itself doesn't know about them.
+type table interface {
+	clone() table
+}
+
+type tableKey[T table] struct {
+	index int
+}
+
+var tableFactories []func() table
+
+func register[T table](create func() T) tableKey[T] {
+	tableFactories = append(tableFactories, func() table { return create() })
+	return tableKey[T]{index: len(tableFactories) - 1}
+}
+
+func (k tableKey[T]) of(st *state) T {
+	return st.tables[k.index].(T)
+}
+
+type state struct {
+	tables []table
+}
+
+func (s *state) clone() *state {
+	tables := make([]table, len(s.tables))
+	for i, t := range s.tables {
+		tables[i] = t.clone()
+	}
+
+	return &state{tables: tables}
+}
+
+// Store keeps the whole data set in memory. Transactions work on a private copy
+// of the state and fail with repository.ErrConflict on commit if another write
+// was committed since they began, mimicking serializable isolation.
+type Store struct {
+	mu         sync.RWMutex
+	state      *state
+	generation uint64
+}
+
+func NewStore() *Store {
+	tables := make([]table, len(tableFactories))
+	for i, create := range tableFactories {
+		tables[i] = create()
+	}
+
+	return &Store{
+		state: &state{tables: tables},
+	}
+}
+
+type txKey struct{}
+
+type tx struct {
+	store      *Store
+	state      *state
+	generation uint64
+	dirty      bool
+	done       bool
+}
+
+func (s *Store) BeginTx(ctx context.Context) (context.Context, repository.Tx, error) {
+	s.mu.RLock()
+	t := &tx{store: s, state: s.state.clone(), generation: s.generation}
+	s.mu.RUnlock()
+
+	return context.WithValue(ctx, txKey{}, t), t, nil
+}
+
+func (t *tx) Commit() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+
+	if !t.dirty {
+		return nil
+	}
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if t.store.generation != t.generation {
+		return repository.ErrConflict
+	}
+
+	t.store.state = t.state
+	t.store.generation++
+
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+
+	return nil
+}
+
+func (s *Store) read(ctx context.Context, fn func(st *state) error) error {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		return fn(t.state)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(s.state)
+}
+
+func (s *Store) write(ctx context.Context, fn func(st *state) error) error {
+	if t, ok := ctx.Value(txKey{}).(*tx); ok && t.store == s {
+		err := fn(t.state)
+		if err == nil {
+			t.dirty = true
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := fn(s.state)
+	if err == nil {
+		s.generation++
+	}
+
+	return err
+}
+
+func paginate[T any](items []T, limit int, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// paginatePage pages items sorted by name and id, skipping those up to the
+// keyset position of request first.
+func paginatePage[T any](items []T, request page.Request, key func(T) page.Key) []T {
+	if after := request.After; after != nil {
+		items = slices.DeleteFunc(items, func(item T) bool {
+			k := key(item)
+			return cmp.Or(cmp.Compare(k.Name, after.Name), cmp.Compare(k.Id.String(), after.Id.String())) <= 0
+		})
+	}
+
+	return paginate(items, request.Limit, request.Offset)
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
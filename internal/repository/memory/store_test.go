@@ -0,0 +1,165 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func newTender(t *testing.T, store *memory.Store) tender.Tender {
+	t.Helper()
+
+	organizationId := store.AddOrganization("org")
+	store.AddEmployee("user")
+
+	created, err := memory.NewTenderRepository(store).Create(context.Background(), tender.Tender{
+		Name:            "Tender",
+		Status:          tender.TenderStatusCreated,
+		Version:         1,
+		OrganizationId:  uuid.MustParse(organizationId),
+		CreatorUsername: "user",
+	})
+	if err != nil {
+		t.Fatalf("create tender: %v", err)
+	}
+
+	return created
+}
+
+func TestRollbackDiscardsChanges(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewTenderRepository(store)
+	created := newTender(t, store)
+
+	txCtx, tx, err := repo.BeginTx(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created.Name = "Renamed"
+	if err = repo.Update(txCtx, created); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(context.Background(), created.Id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Tender" {
+		t.Fatalf("name = %q, want %q", got.Name, "Tender")
+	}
+}
+
+func TestConcurrentCommitConflicts(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewTenderRepository(store)
+	created := newTender(t, store)
+
+	firstCtx, first, _ := repo.BeginTx(context.Background())
+	secondCtx, second, _ := repo.BeginTx(context.Background())
+
+	created.Name = "First"
+	if err := repo.Update(firstCtx, created); err != nil {
+		t.Fatal(err)
+	}
+
+	created.Name = "Second"
+	if err := repo.Update(secondCtx, created); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := first.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := second.Commit(); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("second commit err = %v, want %v", err, repository.ErrConflict)
+	}
+
+	got, _ := repo.Get(context.Background(), created.Id.String())
+	if got.Name != "First" {
+		t.Fatalf("name = %q, want %q", got.Name, "First")
+	}
+}
+
+func TestCreateRejectsUnknownOrganization(t *testing.T) {
+	store := memory.NewStore()
+	store.AddEmployee("user")
+
+	_, err := memory.NewTenderRepository(store).Create(context.Background(), tender.Tender{
+		Name:            "Tender",
+		OrganizationId:  uuid.New(),
+		CreatorUsername: "user",
+	})
+	if !errors.Is(err, repository.ErrInvalidReference) {
+		t.Fatalf("err = %v, want %v", err, repository.ErrInvalidReference)
+	}
+}
+
+func TestInTxRetriesConflict(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewTenderRepository(store)
+	created := newTender(t, store)
+
+	attempts := 0
+	err := repository.InTx(context.Background(), repo, func(ctx context.Context) error {
+		attempts++
+
+		if attempts == 1 {
+			concurrent := created
+			concurrent.Name = "Concurrent"
+			if err := repo.Update(context.Background(), concurrent); err != nil {
+				return err
+			}
+		}
+
+		renamed := created
+		renamed.Name = "Renamed"
+		return repo.Update(ctx, renamed)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+
+	got, _ := repo.Get(context.Background(), created.Id.String())
+	if got.Name != "Renamed" {
+		t.Fatalf("name = %q, want %q", got.Name, "Renamed")
+	}
+}
+
+func TestInTxRollsBackOnError(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewTenderRepository(store)
+	created := newTender(t, store)
+
+	failure := errors.New("failure")
+	err := repository.InTx(context.Background(), repo, func(ctx context.Context) error {
+		renamed := created
+		renamed.Name = "Renamed"
+		if err := repo.Update(ctx, renamed); err != nil {
+			return err
+		}
+
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want %v", err, failure)
+	}
+
+	got, _ := repo.Get(context.Background(), created.Id.String())
+	if got.Name != "Tender" {
+		t.Fatalf("name = %q, want %q", got.Name, "Tender")
+	}
+}
@@ -0,0 +1,227 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"time"
+)
+
+type tenderState struct {
+	tenders     map[string]tender.Tender
+	tenderDiffs []tender.Tender
+}
+
+var tenderTable = register(func() *tenderState {
+	return &tenderState{
+		tenders: make(map[string]tender.Tender),
+	}
+})
+
+func (t *tenderState) clone() table {
+	return &tenderState{
+		tenders:     maps.Clone(t.tenders),
+		tenderDiffs: slices.Clone(t.tenderDiffs),
+	}
+}
+
+type TenderRepository struct {
+	*Store
+}
+
+func NewTenderRepository(store *Store) *TenderRepository {
+	return &TenderRepository{
+		Store: store,
+	}
+}
+
+func (r *TenderRepository) Create(ctx context.Context, t tender.Tender) (tender.Tender, error) {
+	err := r.write(ctx, func(st *state) error {
+		if _, ok := organizationTable.of(st).organizations[t.OrganizationId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		if _, ok := st.employeeByUsername(t.CreatorUsername); !ok {
+			return repository.ErrInvalidReference
+		}
+
+		t.Id = uuid.New()
+		t.CreatedAt = now()
+		tenderTable.of(st).tenders[t.Id.String()] = t
+
+		return nil
+	})
+
+	return t, err
+}
+
+func (r *TenderRepository) Get(ctx context.Context, tenderId string) (tender.Tender, error) {
+	var t tender.Tender
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if t, ok = tenderTable.of(st).tenders[tenderId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return t, err
+}
+
+func (r *TenderRepository) GetVersion(ctx context.Context, tenderId string, version int) (tender.Tender, error) {
+	var t tender.Tender
+
+	err := r.read(ctx, func(st *state) error {
+		for _, diff := range tenderTable.of(st).tenderDiffs {
+			if diff.Id.String() == tenderId && diff.Version == version {
+				t = diff
+				return nil
+			}
+		}
+
+		return repository.ErrNotFound
+	})
+
+	return t, err
+}
+
+func (r *TenderRepository) ListVersions(ctx context.Context, tenderId string, withDrafts bool, limit int, offset int) ([]tender.Tender, error) {
+	var versions []tender.Tender
+
+	err := r.read(ctx, func(st *state) error {
+		for _, diff := range tenderTable.of(st).tenderDiffs {
+			if diff.Id.String() == tenderId && (withDrafts || diff.Status != tender.TenderStatusCreated) {
+				versions = append(versions, diff)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(versions, func(a, b tender.Tender) int {
+		return cmp.Compare(b.Version, a.Version)
+	})
+
+	return paginate(versions, limit, offset), nil
+}
+
+func (r *TenderRepository) Update(ctx context.Context, t tender.Tender) error {
+	return r.write(ctx, func(st *state) error {
+		ts := tenderTable.of(st)
+
+		if _, ok := ts.tenders[t.Id.String()]; !ok {
+			return repository.ErrNotFound
+		}
+
+		ts.tenders[t.Id.String()] = t
+
+		return nil
+	})
+}
+
+func (r *TenderRepository) InsertDiff(ctx context.Context, t tender.Tender) error {
+	return r.write(ctx, func(st *state) error {
+		ts := tenderTable.of(st)
+
+		if _, ok := ts.tenders[t.Id.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		for _, diff := range ts.tenderDiffs {
+			if diff.Id == t.Id && diff.Version == t.Version {
+				return repository.ErrDuplicate
+			}
+		}
+
+		ts.tenderDiffs = append(ts.tenderDiffs, t)
+
+		return nil
+	})
+}
+
+func (r *TenderRepository) ListPublished(ctx context.Context, serviceType tender.TenderServiceType, request page.Request) ([]tender.Tender, error) {
+	tenders, err := r.list(ctx, published(serviceType))
+	return paginatePage(tenders, request, tenderKey), err
+}
+
+func (r *TenderRepository) CountPublished(ctx context.Context, serviceType tender.TenderServiceType) (int, error) {
+	tenders, err := r.list(ctx, published(serviceType))
+	return len(tenders), err
+}
+
+func (r *TenderRepository) ListByCreator(ctx context.Context, username string, organizationId string, request page.Request) ([]tender.Tender, error) {
+	tenders, err := r.list(ctx, createdBy(username, organizationId))
+	return paginatePage(tenders, request, tenderKey), err
+}
+
+func (r *TenderRepository) CountByCreator(ctx context.Context, username string, organizationId string) (int, error) {
+	tenders, err := r.list(ctx, createdBy(username, organizationId))
+	return len(tenders), err
+}
+
+func (r *TenderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]tender.Tender, error) {
+	due := func(at *time.Time) bool {
+		return at != nil && !at.After(now)
+	}
+
+	tenders, err := r.list(ctx, func(t tender.Tender) bool {
+		return (t.Status == tender.TenderStatusCreated && due(t.PublishAt)) ||
+			(t.Status != tender.TenderStatusClosed && due(t.CloseAt))
+	})
+
+	return paginate(tenders, limit, 0), err
+}
+
+// TryLock always succeeds: the store serializes writers itself.
+func (r *TenderRepository) TryLock(ctx context.Context, key string) (bool, error) {
+	return true, nil
+}
+
+func (r *TenderRepository) list(ctx context.Context, match func(t tender.Tender) bool) ([]tender.Tender, error) {
+	var tenders []tender.Tender
+
+	err := r.read(ctx, func(st *state) error {
+		for _, t := range tenderTable.of(st).tenders {
+			if match(t) {
+				tenders = append(tenders, t)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(tenders, func(a, b tender.Tender) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return tenders, nil
+}
+
+func published(serviceType tender.TenderServiceType) func(t tender.Tender) bool {
+	return func(t tender.Tender) bool {
+		return t.Status == tender.TenderStatusPublished && (serviceType == "" || t.ServiceType == serviceType)
+	}
+}
+
+func createdBy(username string, organizationId string) func(t tender.Tender) bool {
+	return func(t tender.Tender) bool {
+		return t.CreatorUsername == username && (organizationId == "" || t.OrganizationId.String() == organizationId)
+	}
+}
+
+func tenderKey(t tender.Tender) page.Key {
+	return page.Key{Name: t.Name, Id: t.Id}
+}
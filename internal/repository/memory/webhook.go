@@ -0,0 +1,300 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"time"
+)
+
+type webhookState struct {
+	subscriptions map[string]webhook.Subscription
+	deliveries    map[string]webhook.Delivery
+	delivered     map[string]time.Time
+	deadLetters   map[string]webhook.Delivery
+}
+
+var webhookTable = register(func() *webhookState {
+	return &webhookState{
+		subscriptions: make(map[string]webhook.Subscription),
+		deliveries:    make(map[string]webhook.Delivery),
+		delivered:     make(map[string]time.Time),
+		deadLetters:   make(map[string]webhook.Delivery),
+	}
+})
+
+func (t *webhookState) clone() table {
+	return &webhookState{
+		subscriptions: maps.Clone(t.subscriptions),
+		deliveries:    maps.Clone(t.deliveries),
+		delivered:     maps.Clone(t.delivered),
+		deadLetters:   maps.Clone(t.deadLetters),
+	}
+}
+
+type WebhookRepository struct {
+	*Store
+}
+
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{
+		Store: store,
+	}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error) {
+	err := r.write(ctx, func(st *state) error {
+		if _, ok := organizationTable.of(st).organizations[s.OrganizationId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		s.Id = uuid.New()
+		s.CreatedAt = now()
+		webhookTable.of(st).subscriptions[s.Id.String()] = s
+
+		return nil
+	})
+
+	return s, err
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, subscriptionId string) (webhook.Subscription, error) {
+	var s webhook.Subscription
+
+	err := r.read(ctx, func(st *state) error {
+		var ok bool
+		if s, ok = webhookTable.of(st).subscriptions[subscriptionId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return s, err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, organizationId string, limit int, offset int) ([]webhook.Subscription, error) {
+	subscriptions, err := r.subscriptions(ctx, func(s webhook.Subscription) bool {
+		return s.OrganizationId.String() == organizationId
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paginate(subscriptions, limit, offset), nil
+}
+
+func (r *WebhookRepository) ListSubscribers(ctx context.Context, organizationId string, eventType event.Type) ([]webhook.Subscription, error) {
+	return r.subscriptions(ctx, func(s webhook.Subscription) bool {
+		return s.OrganizationId.String() == organizationId && (len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType))
+	})
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionId string) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.subscriptions[subscriptionId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(ws.subscriptions, subscriptionId)
+		for id, d := range ws.deliveries {
+			if d.SubscriptionId.String() == subscriptionId {
+				delete(ws.deliveries, id)
+				delete(ws.delivered, id)
+			}
+		}
+		for id, d := range ws.deadLetters {
+			if d.SubscriptionId.String() == subscriptionId {
+				delete(ws.deadLetters, id)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, d webhook.Delivery) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.subscriptions[d.SubscriptionId.String()]; !ok {
+			return repository.ErrInvalidReference
+		}
+
+		for _, existing := range ws.deliveries {
+			if existing.SubscriptionId == d.SubscriptionId && existing.EventId == d.EventId {
+				return nil
+			}
+		}
+
+		d.Id = uuid.New()
+		d.CreatedAt = now()
+		ws.deliveries[d.Id.String()] = d
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+
+	err := r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		for id, d := range ws.deliveries {
+			if _, delivered := ws.delivered[id]; !delivered && !d.NextAttemptAt.After(now) {
+				deliveries = append(deliveries, d)
+			}
+		}
+
+		slices.SortFunc(deliveries, func(a, b webhook.Delivery) int {
+			return cmp.Or(a.NextAttemptAt.Compare(*b.NextAttemptAt), cmp.Compare(a.EventId, b.EventId))
+		})
+		deliveries = paginate(deliveries, limit, 0)
+
+		for i := range deliveries {
+			deliveries[i].NextAttemptAt = &leaseUntil
+			ws.deliveries[deliveries[i].Id.String()] = deliveries[i]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryId string, at time.Time) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.deliveries[deliveryId]; !ok {
+			return repository.ErrNotFound
+		}
+
+		ws.delivered[deliveryId] = at
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) RescheduleDelivery(ctx context.Context, d webhook.Delivery) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.deliveries[d.Id.String()]; !ok {
+			return repository.ErrNotFound
+		}
+
+		ws.deliveries[d.Id.String()] = d
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) MoveToDeadLetter(ctx context.Context, d webhook.Delivery, failedAt time.Time) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.deliveries[d.Id.String()]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(ws.deliveries, d.Id.String())
+		d.NextAttemptAt = nil
+		d.FailedAt = &failedAt
+		ws.deadLetters[d.Id.String()] = d
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, organizationId string, limit int, offset int) ([]webhook.Delivery, error) {
+	var deliveries []webhook.Delivery
+
+	err := r.read(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		for _, d := range ws.deadLetters {
+			if ws.subscriptions[d.SubscriptionId.String()].OrganizationId.String() == organizationId {
+				deliveries = append(deliveries, d)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(deliveries, func(a, b webhook.Delivery) int {
+		return cmp.Or(b.FailedAt.Compare(*a.FailedAt), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return paginate(deliveries, limit, offset), nil
+}
+
+func (r *WebhookRepository) GetDeadLetter(ctx context.Context, organizationId string, deliveryId string) (webhook.Delivery, error) {
+	var d webhook.Delivery
+
+	err := r.read(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		var ok bool
+		d, ok = ws.deadLetters[deliveryId]
+		if !ok || ws.subscriptions[d.SubscriptionId.String()].OrganizationId.String() != organizationId {
+			return repository.ErrNotFound
+		}
+
+		return nil
+	})
+
+	return d, err
+}
+
+func (r *WebhookRepository) Requeue(ctx context.Context, d webhook.Delivery) error {
+	return r.write(ctx, func(st *state) error {
+		ws := webhookTable.of(st)
+
+		if _, ok := ws.deadLetters[d.Id.String()]; !ok {
+			return repository.ErrNotFound
+		}
+
+		delete(ws.deadLetters, d.Id.String())
+		ws.deliveries[d.Id.String()] = d
+
+		return nil
+	})
+}
+
+func (r *WebhookRepository) subscriptions(ctx context.Context, match func(s webhook.Subscription) bool) ([]webhook.Subscription, error) {
+	var subscriptions []webhook.Subscription
+
+	err := r.read(ctx, func(st *state) error {
+		for _, s := range webhookTable.of(st).subscriptions {
+			if match(s) {
+				subscriptions = append(subscriptions, s)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(subscriptions, func(a, b webhook.Subscription) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id.String(), b.Id.String()))
+	})
+
+	return subscriptions, nil
+}
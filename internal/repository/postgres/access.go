@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"github.com/lib/pq"
+)
+
+type AccessRepository struct {
+	*Transactor
+}
+
+func NewAccessRepository(transactor *Transactor) *AccessRepository {
+	return &AccessRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *AccessRepository) UserExists(ctx context.Context, username string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM employee WHERE username = $1)"
+
+	var exists bool
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, username).Scan(&exists)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return exists, nil
+}
+
+func (r *AccessRepository) UserId(ctx context.Context, username string) (string, error) {
+	query := "SELECT id FROM employee WHERE username = $1"
+
+	var userId string
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, username).Scan(&userId)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return userId, nil
+}
+
+func (r *AccessRepository) Role(ctx context.Context, username string, organizationId string) (access.Role, error) {
+	if !validId(organizationId) {
+		return "", repository.ErrNotFound
+	}
+
+	query := `
+    SELECT r.role
+    FROM organization_responsible r
+    JOIN employee e ON e.id = r.user_id
+    WHERE e.username = $1
+    AND r.organization_id = $2`
+
+	var role access.Role
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, username, organizationId).Scan(&role)
+	if err != nil {
+		return "", mapError(err)
+	}
+
+	return role, nil
+}
+
+func (r *AccessRepository) Organizations(ctx context.Context, username string, roles []access.Role) ([]string, error) {
+	query := `
+    SELECT r.organization_id
+    FROM organization_responsible r
+    JOIN employee e ON e.id = r.user_id
+    WHERE e.username = $1
+    AND r.role::text = ANY($2)`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, username, pq.Array(roleNames(roles)))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var organizationIds []string
+	for rows.Next() {
+		var organizationId string
+		if err = rows.Scan(&organizationId); err != nil {
+			return nil, err
+		}
+		organizationIds = append(organizationIds, organizationId)
+	}
+
+	return organizationIds, rows.Err()
+}
+
+func (r *AccessRepository) CountMembers(ctx context.Context, organizationId string, roles []access.Role) (int, error) {
+	query := "SELECT COUNT(*) FROM organization_responsible WHERE organization_id = $1 AND role::text = ANY($2)"
+
+	var count int
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, organizationId, pq.Array(roleNames(roles))).Scan(&count)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return count, nil
+}
+
+func roleNames(roles []access.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+
+	return names
+}
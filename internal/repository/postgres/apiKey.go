@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"github.com/lib/pq"
+	"time"
+)
+
+const apiKeyColumns = "k.id, k.organization_id, k.name, k.scopes, k.prefix, k.created_by, e.username, k.key_hash, k.created_at, k.last_used_at, k.revoked_at"
+
+type ApiKeyRepository struct {
+	*Transactor
+}
+
+func NewApiKeyRepository(transactor *Transactor) *ApiKeyRepository {
+	return &ApiKeyRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *ApiKeyRepository) CreateKey(ctx context.Context, k apikey.Key) (apikey.Key, error) {
+	query := "INSERT INTO api_key (organization_id, name, scopes, prefix, key_hash, created_by) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at"
+
+	scopes := make([]string, len(k.Scopes))
+	for i, scope := range k.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, k.OrganizationId, k.Name, pq.Array(scopes), k.Prefix, k.KeyHash, k.CreatorId).Scan(&k.Id, &k.CreatedAt)
+	if err != nil {
+		return k, mapError(err)
+	}
+
+	return k, nil
+}
+
+func (r *ApiKeyRepository) GetKey(ctx context.Context, keyId string) (apikey.Key, error) {
+	if !validId(keyId) {
+		return apikey.Key{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + apiKeyColumns + " FROM api_key k JOIN employee e ON e.id = k.created_by WHERE k.id = $1"
+
+	k, err := scanApiKey(r.conn(ctx).QueryRowContext(ctx, query, keyId))
+	if err != nil {
+		return k, mapError(err)
+	}
+
+	return k, nil
+}
+
+func (r *ApiKeyRepository) GetKeyByHash(ctx context.Context, keyHash string) (apikey.Key, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_key k JOIN employee e ON e.id = k.created_by WHERE k.key_hash = $1"
+
+	k, err := scanApiKey(r.conn(ctx).QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		return k, mapError(err)
+	}
+
+	return k, nil
+}
+
+func (r *ApiKeyRepository) ListKeys(ctx context.Context, organizationId string, limit int, offset int) ([]apikey.Key, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_key k JOIN employee e ON e.id = k.created_by WHERE k.organization_id = $1 ORDER BY k.created_at, k.id LIMIT $2 OFFSET $3"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, organizationId, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var keys []apikey.Key
+	for rows.Next() {
+		k, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *ApiKeyRepository) RevokeKey(ctx context.Context, keyId string, at time.Time) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "UPDATE api_key SET revoked_at = $1 WHERE id = $2", at, keyId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *ApiKeyRepository) TouchKey(ctx context.Context, keyId string, at time.Time) error {
+	_, err := r.conn(ctx).ExecContext(ctx, "UPDATE api_key SET last_used_at = $1 WHERE id = $2", at, keyId)
+	return mapError(err)
+}
+
+func scanApiKey(row scanner) (apikey.Key, error) {
+	var (
+		k                     apikey.Key
+		scopes                []string
+		lastUsedAt, revokedAt sql.NullTime
+	)
+
+	err := row.Scan(&k.Id, &k.OrganizationId, &k.Name, pq.Array(&scopes), &k.Prefix, &k.CreatorId, &k.CreatorUsername, &k.KeyHash, &k.CreatedAt, &lastUsedAt, &revokedAt)
+
+	k.Scopes = make([]apikey.Scope, len(scopes))
+	for i, scope := range scopes {
+		k.Scopes[i] = apikey.Scope(scope)
+	}
+
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+
+	return k, err
+}
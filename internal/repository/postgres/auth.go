@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"time"
+)
+
+type AuthRepository struct {
+	*Transactor
+}
+
+func NewAuthRepository(transactor *Transactor) *AuthRepository {
+	return &AuthRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *AuthRepository) GetAccount(ctx context.Context, username string) (auth.Account, error) {
+	query := "SELECT id, username, COALESCE(password_hash, '') FROM employee WHERE username = $1"
+
+	var account auth.Account
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, username).Scan(&account.UserId, &account.Username, &account.PasswordHash)
+	if err != nil {
+		return account, mapError(err)
+	}
+
+	return account, nil
+}
+
+func (r *AuthRepository) GetAccountById(ctx context.Context, userId string) (auth.Account, error) {
+	if !validId(userId) {
+		return auth.Account{}, repository.ErrNotFound
+	}
+
+	query := "SELECT id, username, COALESCE(password_hash, '') FROM employee WHERE id = $1"
+
+	var account auth.Account
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, userId).Scan(&account.UserId, &account.Username, &account.PasswordHash)
+	if err != nil {
+		return account, mapError(err)
+	}
+
+	return account, nil
+}
+
+func (r *AuthRepository) SetPasswordHash(ctx context.Context, userId string, passwordHash string) error {
+	query := "UPDATE employee SET password_hash = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, passwordHash, userId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token auth.RefreshToken) error {
+	query := "INSERT INTO refresh_token (employee_id, token_hash, expires_at) VALUES ($1, $2, $3)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, token.UserId, token.TokenHash, token.ExpiresAt)
+	return mapError(err)
+}
+
+func (r *AuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (auth.RefreshToken, error) {
+	query := "SELECT id, employee_id, token_hash, expires_at, revoked_at FROM refresh_token WHERE token_hash = $1 FOR UPDATE"
+
+	var (
+		token     auth.RefreshToken
+		revokedAt sql.NullTime
+	)
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(&token.Id, &token.UserId, &token.TokenHash, &token.ExpiresAt, &revokedAt)
+	if err != nil {
+		return token, mapError(err)
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+func (r *AuthRepository) RevokeRefreshToken(ctx context.Context, tokenId string, at time.Time) error {
+	query := "UPDATE refresh_token SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, at, tokenId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
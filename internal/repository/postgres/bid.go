@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"github.com/lib/pq"
+)
+
+const bidColumns = "id, name, description, status, tender_id, author_type, author_id, version, created_at"
+
+// authoredCondition selects the bids of the authors passed with authorArgs.
+const authoredCondition = `(
+    (author_type = 'User' AND author_id = NULLIF($1, '')::uuid)
+    OR (author_type = 'Organization' AND author_id = ANY($2::uuid[]))
+)`
+
+// tenderBidsCondition selects the bids of a tender the user authored and, with
+// withPublished, the published ones.
+const tenderBidsCondition = "tender_id = $3 AND (" + authoredCondition + " OR ($4 AND status IN ($5, $6, $7, $8)))"
+
+type BidRepository struct {
+	*Transactor
+}
+
+func NewBidRepository(transactor *Transactor) *BidRepository {
+	return &BidRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *BidRepository) Create(ctx context.Context, b bid.Bid) (bid.Bid, error) {
+	query := "INSERT INTO bid (name, description, status, tender_id, author_type, author_id, version) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at"
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, b.Name, b.Description, b.Status, b.TenderId, b.AuthorType, b.AuthorId, b.Version).Scan(&b.Id, &b.CreatedAt)
+	if err != nil {
+		return b, mapError(err)
+	}
+
+	return b, nil
+}
+
+func (r *BidRepository) Get(ctx context.Context, bidId string) (bid.Bid, error) {
+	if !validId(bidId) {
+		return bid.Bid{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + bidColumns + " FROM bid WHERE id = $1"
+
+	b, err := scanBid(r.conn(ctx).QueryRowContext(ctx, query, bidId))
+	if err != nil {
+		return b, mapError(err)
+	}
+
+	return b, nil
+}
+
+func (r *BidRepository) GetVersion(ctx context.Context, bidId string, version int) (bid.Bid, error) {
+	if !validId(bidId) {
+		return bid.Bid{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + bidColumns + " FROM bid_diff WHERE id = $1 AND version = $2"
+
+	b, err := scanBid(r.conn(ctx).QueryRowContext(ctx, query, bidId, version))
+	if err != nil {
+		return b, mapError(err)
+	}
+
+	return b, nil
+}
+
+func (r *BidRepository) ListVersions(ctx context.Context, bidId string, limit int, offset int) ([]bid.Bid, error) {
+	if !validId(bidId) {
+		return nil, nil
+	}
+
+	query := "SELECT " + bidColumns + " FROM bid_diff WHERE id = $1 ORDER BY version DESC LIMIT $2 OFFSET $3"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, bidId, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanBids(rows)
+}
+
+func (r *BidRepository) Update(ctx context.Context, b bid.Bid) error {
+	query := "UPDATE bid SET name = $1, description = $2, status = $3, tender_id = $4, author_type = $5, author_id = $6, version = $7, created_at = $8 WHERE id = $9"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, b.Name, b.Description, b.Status, b.TenderId, b.AuthorType, b.AuthorId, b.Version, b.CreatedAt, b.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *BidRepository) InsertDiff(ctx context.Context, b bid.Bid) error {
+	query := "INSERT INTO bid_diff (" + bidColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, b.Id, b.Name, b.Description, b.Status, b.TenderId, b.AuthorType, b.AuthorId, b.Version, b.CreatedAt)
+	return mapError(err)
+}
+
+func (r *BidRepository) ListByAuthor(ctx context.Context, authors access.Authorship, request page.Request) ([]bid.Bid, error) {
+	query, args := paginate("SELECT "+bidColumns+" FROM bid WHERE "+authoredCondition, request, authorArgs(authors)...)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanBids(rows)
+}
+
+func (r *BidRepository) CountByAuthor(ctx context.Context, authors access.Authorship) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM bid WHERE "+authoredCondition, authorArgs(authors)...)
+}
+
+func (r *BidRepository) ListByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool, request page.Request) ([]bid.Bid, error) {
+	query, args := paginate("SELECT "+bidColumns+" FROM bid WHERE "+tenderBidsCondition, request, tenderBidsArgs(tenderId, authors, withPublished)...)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanBids(rows)
+}
+
+func (r *BidRepository) CountByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM bid WHERE "+tenderBidsCondition, tenderBidsArgs(tenderId, authors, withPublished)...)
+}
+
+func (r *BidRepository) ListByTenderStatus(ctx context.Context, tenderId string, statuses ...bid.BidStatus) ([]bid.Bid, error) {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+
+	query := "SELECT " + bidColumns + " FROM bid WHERE tender_id = $1 AND status::text = ANY($2) ORDER BY name, id"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenderId, pq.Array(names))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanBids(rows)
+}
+
+func (r *BidRepository) HasAuthoredBids(ctx context.Context, tenderId string, authors access.Authorship) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM bid WHERE tender_id = $3 AND " + authoredCondition + ")"
+
+	var exists bool
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, append(authorArgs(authors), tenderId)...).Scan(&exists)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return exists, nil
+}
+
+func (r *BidRepository) SaveDecision(ctx context.Context, bidId string, userId string, decision bid.BidDecision) error {
+	query := `
+    INSERT INTO bid_decision (bid_id, user_id, decision) VALUES ($1, $2, $3)
+    ON CONFLICT (bid_id, user_id) DO UPDATE SET decision = EXCLUDED.decision, created_at = CURRENT_TIMESTAMP`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, bidId, userId, decision)
+	return mapError(err)
+}
+
+func (r *BidRepository) CountDecisions(ctx context.Context, bidId string, decision bid.BidDecision) (int, error) {
+	query := "SELECT COUNT(*) FROM bid_decision WHERE bid_id = $1 AND decision = $2"
+
+	var count int
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, bidId, decision).Scan(&count)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return count, nil
+}
+
+func (r *BidRepository) CreateReview(ctx context.Context, bidId string, userId string, description string) error {
+	query := "INSERT INTO bid_review (bid_id, user_id, description) VALUES ($1, $2, $3)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, bidId, userId, description)
+	return mapError(err)
+}
+
+func (r *BidRepository) ListReviews(ctx context.Context, authors access.Authorship, limit int, offset int) ([]bid.BidReview, error) {
+	query := `
+    SELECT id, description, created_at
+    FROM bid_review
+    WHERE bid_id IN (SELECT id FROM bid WHERE ` + authoredCondition + `)
+    ORDER BY created_at DESC, id
+    LIMIT $3 OFFSET $4`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, append(authorArgs(authors), limit, offset)...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var reviews []bid.BidReview
+
+	for rows.Next() {
+		var review bid.BidReview
+		if err = rows.Scan(&review.Id, &review.Description, &review.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+func authorArgs(authors access.Authorship) []any {
+	return []any{authors.UserId, pq.Array(authors.OrganizationIds)}
+}
+
+func tenderBidsArgs(tenderId string, authors access.Authorship, withPublished bool) []any {
+	return append(authorArgs(authors), tenderId, withPublished, bid.BidStatusPublished, bid.BidStatusApproved, bid.BidStatusRejected, bid.BidStatusNotSelected)
+}
+
+func scanBid(row scanner) (bid.Bid, error) {
+	var b bid.Bid
+
+	err := row.Scan(&b.Id, &b.Name, &b.Description, &b.Status, &b.TenderId, &b.AuthorType, &b.AuthorId, &b.Version, &b.CreatedAt)
+
+	return b, err
+}
+
+func scanBids(rows *sql.Rows) ([]bid.Bid, error) {
+	var bids []bid.Bid
+
+	for rows.Next() {
+		b, err := scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, b)
+	}
+
+	return bids, rows.Err()
+}
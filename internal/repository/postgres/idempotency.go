@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	*Transactor
+}
+
+func NewIdempotencyRepository(transactor *Transactor) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record idempotency.Record, now time.Time) (bool, error) {
+	query := `INSERT INTO idempotency_key (scope, key, fingerprint, expires_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (scope, key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, response_headers = NULL, response_body = NULL, created_at = $5, expires_at = EXCLUDED.expires_at
+WHERE idempotency_key.expires_at <= $5`
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, record.Scope, record.Key, record.Fingerprint, record.ExpiresAt, now)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, scope string, key string) (idempotency.Record, error) {
+	query := "SELECT scope, key, fingerprint, status_code, response_headers, response_body, expires_at FROM idempotency_key WHERE scope = $1 AND key = $2"
+
+	var (
+		record     idempotency.Record
+		statusCode sql.NullInt64
+		headers    []byte
+		body       []byte
+	)
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, scope, key).Scan(&record.Scope, &record.Key, &record.Fingerprint, &statusCode, &headers, &body, &record.ExpiresAt)
+	if err != nil {
+		return record, mapError(err)
+	}
+
+	if statusCode.Valid {
+		record.Response = &idempotency.Response{StatusCode: int(statusCode.Int64), Body: body}
+		if err = json.Unmarshal(headers, &record.Response.Headers); err != nil {
+			return record, err
+		}
+	}
+
+	return record, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope string, key string, response idempotency.Response) error {
+	headers, err := json.Marshal(response.Headers)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE idempotency_key SET status_code = $1, response_headers = $2, response_body = $3 WHERE scope = $4 AND key = $5"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, response.StatusCode, headers, response.Body, scope, key)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, scope string, key string) error {
+	query := "DELETE FROM idempotency_key WHERE scope = $1 AND key = $2"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, scope, key)
+	return mapError(err)
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := "DELETE FROM idempotency_key WHERE expires_at <= $1"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected()
+}
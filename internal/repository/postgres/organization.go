@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+)
+
+const (
+	employeeColumns     = "id, username, COALESCE(first_name, ''), COALESCE(last_name, ''), COALESCE(created_at, CURRENT_TIMESTAMP), COALESCE(updated_at, CURRENT_TIMESTAMP)"
+	organizationColumns = "id, name, COALESCE(description, ''), COALESCE(type::text, ''), COALESCE(created_at, CURRENT_TIMESTAMP), COALESCE(updated_at, CURRENT_TIMESTAMP)"
+)
+
+type OrganizationRepository struct {
+	*Transactor
+}
+
+func NewOrganizationRepository(transactor *Transactor) *OrganizationRepository {
+	return &OrganizationRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *OrganizationRepository) CreateEmployee(ctx context.Context, e organization.Employee) (organization.Employee, error) {
+	query := "INSERT INTO employee (username, first_name, last_name, password_hash) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING " + employeeColumns
+
+	created, err := scanEmployee(r.conn(ctx).QueryRowContext(ctx, query, e.Username, e.FirstName, e.LastName, e.PasswordHash))
+	if err != nil {
+		return created, mapError(err)
+	}
+
+	return created, nil
+}
+
+func (r *OrganizationRepository) GetEmployee(ctx context.Context, employeeId string) (organization.Employee, error) {
+	if !validId(employeeId) {
+		return organization.Employee{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + employeeColumns + " FROM employee WHERE id = $1"
+
+	e, err := scanEmployee(r.conn(ctx).QueryRowContext(ctx, query, employeeId))
+	if err != nil {
+		return e, mapError(err)
+	}
+
+	return e, nil
+}
+
+func (r *OrganizationRepository) ListEmployees(ctx context.Context, limit int, offset int) ([]organization.Employee, error) {
+	query := "SELECT " + employeeColumns + " FROM employee ORDER BY username LIMIT $1 OFFSET $2"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var employees []organization.Employee
+	for rows.Next() {
+		e, err := scanEmployee(rows)
+		if err != nil {
+			return nil, err
+		}
+		employees = append(employees, e)
+	}
+
+	return employees, rows.Err()
+}
+
+func (r *OrganizationRepository) UpdateEmployee(ctx context.Context, e organization.Employee) (organization.Employee, error) {
+	query := "UPDATE employee SET first_name = $1, last_name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 RETURNING " + employeeColumns
+
+	updated, err := scanEmployee(r.conn(ctx).QueryRowContext(ctx, query, e.FirstName, e.LastName, e.Id))
+	if err != nil {
+		return updated, mapError(err)
+	}
+
+	return updated, nil
+}
+
+func (r *OrganizationRepository) DeleteEmployee(ctx context.Context, employeeId string) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM employee WHERE id = $1", employeeId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *OrganizationRepository) CreateOrganization(ctx context.Context, o organization.Organization) (organization.Organization, error) {
+	query := "INSERT INTO organization (name, description, type) VALUES ($1, $2, $3) RETURNING " + organizationColumns
+
+	created, err := scanOrganization(r.conn(ctx).QueryRowContext(ctx, query, o.Name, o.Description, o.Type))
+	if err != nil {
+		return created, mapError(err)
+	}
+
+	return created, nil
+}
+
+func (r *OrganizationRepository) GetOrganization(ctx context.Context, organizationId string) (organization.Organization, error) {
+	if !validId(organizationId) {
+		return organization.Organization{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + organizationColumns + " FROM organization WHERE id = $1"
+
+	o, err := scanOrganization(r.conn(ctx).QueryRowContext(ctx, query, organizationId))
+	if err != nil {
+		return o, mapError(err)
+	}
+
+	return o, nil
+}
+
+func (r *OrganizationRepository) ListOrganizations(ctx context.Context, limit int, offset int) ([]organization.Organization, error) {
+	query := "SELECT " + organizationColumns + " FROM organization ORDER BY name, id LIMIT $1 OFFSET $2"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var organizations []organization.Organization
+	for rows.Next() {
+		o, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, o)
+	}
+
+	return organizations, rows.Err()
+}
+
+func (r *OrganizationRepository) UpdateOrganization(ctx context.Context, o organization.Organization) (organization.Organization, error) {
+	query := "UPDATE organization SET name = $1, description = $2, type = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4 RETURNING " + organizationColumns
+
+	updated, err := scanOrganization(r.conn(ctx).QueryRowContext(ctx, query, o.Name, o.Description, o.Type, o.Id))
+	if err != nil {
+		return updated, mapError(err)
+	}
+
+	return updated, nil
+}
+
+func (r *OrganizationRepository) DeleteOrganization(ctx context.Context, organizationId string) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM organization WHERE id = $1", organizationId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *OrganizationRepository) HasTenders(ctx context.Context, organizationId string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM tender WHERE organization_id = $1)"
+
+	var exists bool
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, organizationId).Scan(&exists)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return exists, nil
+}
+
+func (r *OrganizationRepository) CreateResponsible(ctx context.Context, resp organization.Responsible) error {
+	query := "INSERT INTO organization_responsible (organization_id, user_id, role) VALUES ($1, $2, $3)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, resp.OrganizationId, resp.UserId, resp.Role)
+	return mapError(err)
+}
+
+func (r *OrganizationRepository) GetResponsible(ctx context.Context, userId string) (organization.Responsible, error) {
+	if !validId(userId) {
+		return organization.Responsible{}, repository.ErrNotFound
+	}
+
+	query := `
+    SELECT r.organization_id, r.user_id, e.username, r.role
+    FROM organization_responsible r
+    JOIN employee e ON e.id = r.user_id
+    WHERE r.user_id = $1`
+
+	resp, err := scanResponsible(r.conn(ctx).QueryRowContext(ctx, query, userId))
+	if err != nil {
+		return resp, mapError(err)
+	}
+
+	return resp, nil
+}
+
+func (r *OrganizationRepository) ListResponsibles(ctx context.Context, organizationId string, limit int, offset int) ([]organization.Responsible, error) {
+	query := `
+    SELECT r.organization_id, r.user_id, e.username, r.role
+    FROM organization_responsible r
+    JOIN employee e ON e.id = r.user_id
+    WHERE r.organization_id = $1
+    ORDER BY e.username
+    LIMIT $2 OFFSET $3`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, organizationId, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var responsibles []organization.Responsible
+	for rows.Next() {
+		resp, err := scanResponsible(rows)
+		if err != nil {
+			return nil, err
+		}
+		responsibles = append(responsibles, resp)
+	}
+
+	return responsibles, rows.Err()
+}
+
+func (r *OrganizationRepository) DeleteResponsible(ctx context.Context, organizationId string, userId string) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM organization_responsible WHERE organization_id = $1 AND user_id = $2", organizationId, userId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *OrganizationRepository) CountResponsibles(ctx context.Context, organizationId string, role access.Role) (int, error) {
+	query := "SELECT COUNT(*) FROM organization_responsible WHERE organization_id = $1 AND role = $2"
+
+	var count int
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, organizationId, role).Scan(&count)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return count, nil
+}
+
+func scanEmployee(row scanner) (organization.Employee, error) {
+	var e organization.Employee
+	err := row.Scan(&e.Id, &e.Username, &e.FirstName, &e.LastName, &e.CreatedAt, &e.UpdatedAt)
+	return e, err
+}
+
+func scanOrganization(row scanner) (organization.Organization, error) {
+	var o organization.Organization
+	err := row.Scan(&o.Id, &o.Name, &o.Description, &o.Type, &o.CreatedAt, &o.UpdatedAt)
+	return o, err
+}
+
+func scanResponsible(row scanner) (organization.Responsible, error) {
+	var resp organization.Responsible
+	err := row.Scan(&resp.OrganizationId, &resp.UserId, &resp.Username, &resp.Role)
+	return resp, err
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"github.com/lib/pq"
+	"time"
+)
+
+const outboxColumns = "id, type, organization_id, tender_id, bid_id, COALESCE(author_type, ''), COALESCE(author_id::text, ''), public, payload, COALESCE(sequence, 0), created_at"
+
+type OutboxRepository struct {
+	*Transactor
+}
+
+func NewOutboxRepository(transactor *Transactor) *OutboxRepository {
+	return &OutboxRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *OutboxRepository) Append(ctx context.Context, e event.Event) error {
+	query := "INSERT INTO outbox (type, organization_id, tender_id, bid_id, author_type, author_id, public, payload) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, '')::uuid, $7, $8)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, e.Type, e.OrganizationId, e.TenderId, e.BidId, e.AuthorType, e.AuthorId, e.Public, []byte(e.Payload))
+	return mapError(err)
+}
+
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]event.Event, error) {
+	query := "SELECT " + outboxColumns + " FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (r *OutboxRepository) ListAfter(ctx context.Context, afterSequence int64, limit int) ([]event.Event, error) {
+	query := "SELECT " + outboxColumns + " FROM outbox WHERE sequence > $1 ORDER BY sequence LIMIT $2"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, afterSequence, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, ids []int64, at time.Time) ([]int64, error) {
+	query := `
+    UPDATE outbox o
+    SET published_at = $1, sequence = n.sequence
+    FROM (
+        SELECT id, (SELECT COALESCE(MAX(sequence), 0) FROM outbox) + row_number() OVER (ORDER BY array_position($2, id)) AS sequence
+        FROM outbox
+        WHERE id = ANY($2)
+    ) n
+    WHERE o.id = n.id
+    RETURNING o.id, o.sequence`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, at, pq.Array(ids))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	assigned := make(map[int64]int64, len(ids))
+	for rows.Next() {
+		var id, sequence int64
+		if err = rows.Scan(&id, &sequence); err != nil {
+			return nil, err
+		}
+		assigned[id] = sequence
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sequences := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		sequence, ok := assigned[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		sequences = append(sequences, sequence)
+	}
+
+	return sequences, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]event.Event, error) {
+	var events []event.Event
+
+	for rows.Next() {
+		var e event.Event
+		if err := rows.Scan(&e.Id, &e.Type, &e.OrganizationId, &e.TenderId, &e.BidId, &e.AuthorType, &e.AuthorId, &e.Public, &e.Payload, &e.Sequence, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
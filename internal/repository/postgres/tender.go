@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"time"
+)
+
+const tenderColumns = "id, name, description, status, service_type, version, organization_id, creator_username, created_at, publish_at, bid_deadline, close_at"
+
+const publishedCondition = "status = $1 AND ($2::text = '' OR service_type::text = $2)"
+
+type TenderRepository struct {
+	*Transactor
+}
+
+func NewTenderRepository(transactor *Transactor) *TenderRepository {
+	return &TenderRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *TenderRepository) Create(ctx context.Context, t tender.Tender) (tender.Tender, error) {
+	query := "INSERT INTO tender (name, description, status, service_type, version, organization_id, creator_username, publish_at, bid_deadline, close_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at"
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, t.Name, t.Description, t.Status, t.ServiceType, t.Version, t.OrganizationId, t.CreatorUsername, t.PublishAt, t.BidDeadline, t.CloseAt).Scan(&t.Id, &t.CreatedAt)
+	if err != nil {
+		return t, mapError(err)
+	}
+
+	return t, nil
+}
+
+func (r *TenderRepository) Get(ctx context.Context, tenderId string) (tender.Tender, error) {
+	if !validId(tenderId) {
+		return tender.Tender{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + tenderColumns + " FROM tender WHERE id = $1"
+
+	t, err := scanTender(r.conn(ctx).QueryRowContext(ctx, query, tenderId))
+	if err != nil {
+		return t, mapError(err)
+	}
+
+	return t, nil
+}
+
+func (r *TenderRepository) GetVersion(ctx context.Context, tenderId string, version int) (tender.Tender, error) {
+	if !validId(tenderId) {
+		return tender.Tender{}, repository.ErrNotFound
+	}
+
+	query := "SELECT " + tenderColumns + " FROM tender_diff WHERE id = $1 AND version = $2"
+
+	t, err := scanTender(r.conn(ctx).QueryRowContext(ctx, query, tenderId, version))
+	if err != nil {
+		return t, mapError(err)
+	}
+
+	return t, nil
+}
+
+func (r *TenderRepository) ListVersions(ctx context.Context, tenderId string, withDrafts bool, limit int, offset int) ([]tender.Tender, error) {
+	if !validId(tenderId) {
+		return nil, nil
+	}
+
+	query := "SELECT " + tenderColumns + " FROM tender_diff WHERE id = $1 AND ($2 OR status <> $3) ORDER BY version DESC LIMIT $4 OFFSET $5"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tenderId, withDrafts, tender.TenderStatusCreated, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanTenders(rows)
+}
+
+func (r *TenderRepository) Update(ctx context.Context, t tender.Tender) error {
+	query := "UPDATE tender SET name = $1, description = $2, status = $3, service_type = $4, version = $5, organization_id = $6, creator_username = $7, created_at = $8, publish_at = $9, bid_deadline = $10, close_at = $11 WHERE id = $12"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, t.Name, t.Description, t.Status, t.ServiceType, t.Version, t.OrganizationId, t.CreatorUsername, t.CreatedAt, t.PublishAt, t.BidDeadline, t.CloseAt, t.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *TenderRepository) InsertDiff(ctx context.Context, t tender.Tender) error {
+	query := "INSERT INTO tender_diff (" + tenderColumns + ") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, t.Id, t.Name, t.Description, t.Status, t.ServiceType, t.Version, t.OrganizationId, t.CreatorUsername, t.CreatedAt, t.PublishAt, t.BidDeadline, t.CloseAt)
+	return mapError(err)
+}
+
+func (r *TenderRepository) ListPublished(ctx context.Context, serviceType tender.TenderServiceType, request page.Request) ([]tender.Tender, error) {
+	query, args := paginate("SELECT "+tenderColumns+" FROM tender WHERE "+publishedCondition, request, tender.TenderStatusPublished, string(serviceType))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanTenders(rows)
+}
+
+func (r *TenderRepository) CountPublished(ctx context.Context, serviceType tender.TenderServiceType) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM tender WHERE "+publishedCondition, tender.TenderStatusPublished, string(serviceType))
+}
+
+// createdByCondition selects the tenders of creator_username, limited to the
+// organization in $2 unless it is empty.
+const createdByCondition = "creator_username = $1 AND ($2 = '' OR organization_id::text = $2)"
+
+func (r *TenderRepository) ListByCreator(ctx context.Context, username string, organizationId string, request page.Request) ([]tender.Tender, error) {
+	query, args := paginate("SELECT "+tenderColumns+" FROM tender WHERE "+createdByCondition, request, username, organizationId)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanTenders(rows)
+}
+
+func (r *TenderRepository) CountByCreator(ctx context.Context, username string, organizationId string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM tender WHERE "+createdByCondition, username, organizationId)
+}
+
+func (r *TenderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]tender.Tender, error) {
+	query := `
+    SELECT ` + tenderColumns + `
+    FROM tender
+    WHERE (status = $1 AND publish_at <= $3)
+    OR (status IN ($1, $2) AND close_at <= $3)
+    ORDER BY LEAST(publish_at, close_at), id
+    LIMIT $4`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, tender.TenderStatusCreated, tender.TenderStatusPublished, now, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanTenders(rows)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTender(row scanner) (tender.Tender, error) {
+	var t tender.Tender
+
+	err := row.Scan(&t.Id, &t.Name, &t.Description, &t.Status, &t.ServiceType, &t.Version, &t.OrganizationId, &t.CreatorUsername, &t.CreatedAt, &t.PublishAt, &t.BidDeadline, &t.CloseAt)
+
+	return t, err
+}
+
+func scanTenders(rows *sql.Rows) ([]tender.Tender, error) {
+	var tenders []tender.Tender
+
+	for rows.Next() {
+		t, err := scanTender(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, t)
+	}
+
+	return tenders, rows.Err()
+}
+
+func checkAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+type tx struct {
+	*sql.Tx
+}
+
+func (t tx) Commit() error {
+	return mapError(t.Tx.Commit())
+}
+
+type Transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+func (t *Transactor) BeginTx(ctx context.Context) (context.Context, repository.Tx, error) {
+	sqlTx, err := t.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return context.WithValue(ctx, txKey{}, sqlTx), tx{sqlTx}, nil
+}
+
+// TryLock takes a transaction-scoped advisory lock on key, so it must be
+// called inside a transaction. It reports false when another session holds it.
+func (t *Transactor) TryLock(ctx context.Context, key string) (bool, error) {
+	var locked bool
+
+	err := t.conn(ctx).QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", key).Scan(&locked)
+	if err != nil {
+		return false, mapError(err)
+	}
+
+	return locked, nil
+}
+
+func (t *Transactor) conn(ctx context.Context) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return t.db
+}
+
+func mapError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23503", "22P02", "P0001":
+			return repository.ErrInvalidReference
+		case "40001", "40P01":
+			return repository.ErrConflict
+		case "23505":
+			return repository.ErrDuplicate
+		}
+	}
+
+	return err
+}
+
+func validId(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+func (t *Transactor) count(ctx context.Context, query string, args ...any) (int, error) {
+	var count int
+
+	err := t.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return count, nil
+}
+
+// paginate completes a query that ends in a WHERE clause with the keyset
+// condition, the name and id order, and the limit and offset of request.
+func paginate(query string, request page.Request, args ...any) (string, []any) {
+	if request.After != nil {
+		args = append(args, request.After.Name, request.After.Id)
+		query += fmt.Sprintf(" AND (name, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	args = append(args, request.Limit, request.Offset)
+	query += fmt.Sprintf(" ORDER BY name, id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	return query, args
+}
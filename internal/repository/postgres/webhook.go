@@ -0,0 +1,266 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
+	"github.com/lib/pq"
+	"slices"
+	"time"
+)
+
+const (
+	subscriptionColumns = "id, organization_id, url, secret, event_types, created_at"
+	deliveryColumns     = "id, subscription_id, event_id, event_type, payload, attempts, next_attempt_at, COALESCE(last_error, ''), created_at"
+	deadLetterColumns   = "d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.attempts, d.last_error, d.created_at, d.failed_at"
+)
+
+type WebhookRepository struct {
+	*Transactor
+}
+
+func NewWebhookRepository(transactor *Transactor) *WebhookRepository {
+	return &WebhookRepository{
+		Transactor: transactor,
+	}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s webhook.Subscription) (webhook.Subscription, error) {
+	query := "INSERT INTO webhook_subscription (organization_id, url, secret, event_types) VALUES ($1, $2, $3, $4) RETURNING id, created_at"
+
+	eventTypes := make([]string, len(s.EventTypes))
+	for i, eventType := range s.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	err := r.conn(ctx).QueryRowContext(ctx, query, s.OrganizationId, s.Url, s.Secret, pq.Array(eventTypes)).Scan(&s.Id, &s.CreatedAt)
+	if err != nil {
+		return s, mapError(err)
+	}
+
+	return s, nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, subscriptionId string) (webhook.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscription WHERE id = $1"
+
+	s, err := scanSubscription(r.conn(ctx).QueryRowContext(ctx, query, subscriptionId))
+	if err != nil {
+		return s, mapError(err)
+	}
+
+	return s, nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, organizationId string, limit int, offset int) ([]webhook.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscription WHERE organization_id = $1 ORDER BY created_at, id LIMIT $2 OFFSET $3"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, organizationId, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+func (r *WebhookRepository) ListSubscribers(ctx context.Context, organizationId string, eventType event.Type) ([]webhook.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM webhook_subscription WHERE organization_id = $1 AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, organizationId, eventType)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionId string) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM webhook_subscription WHERE id = $1", subscriptionId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, d webhook.Delivery) error {
+	query := `INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload, next_attempt_at) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, d.SubscriptionId, d.EventId, d.EventType, []byte(d.Payload), d.NextAttemptAt)
+	return mapError(err)
+}
+
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]webhook.Delivery, error) {
+	query := `
+    UPDATE webhook_delivery SET next_attempt_at = $2
+    WHERE id IN (
+        SELECT id FROM webhook_delivery
+        WHERE delivered_at IS NULL AND next_attempt_at <= $1
+        ORDER BY next_attempt_at, event_id
+        LIMIT $3
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING ` + deliveryColumns
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var deliveries []webhook.Delivery
+	for rows.Next() {
+		var d webhook.Delivery
+		err = rows.Scan(&d.Id, &d.SubscriptionId, &d.EventId, &d.EventType, &d.Payload, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(deliveries, func(a, b webhook.Delivery) int {
+		return cmp.Compare(a.EventId, b.EventId)
+	})
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryId string, at time.Time) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "UPDATE webhook_delivery SET delivered_at = $1 WHERE id = $2", at, deliveryId)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *WebhookRepository) RescheduleDelivery(ctx context.Context, d webhook.Delivery) error {
+	query := "UPDATE webhook_delivery SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4"
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, d.Attempts, d.NextAttemptAt, d.LastError, d.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *WebhookRepository) MoveToDeadLetter(ctx context.Context, d webhook.Delivery, failedAt time.Time) error {
+	query := "INSERT INTO webhook_dead_letter (id, subscription_id, event_id, event_type, payload, attempts, last_error, created_at, failed_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, d.Id, d.SubscriptionId, d.EventId, d.EventType, []byte(d.Payload), d.Attempts, d.LastError, d.CreatedAt, failedAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM webhook_delivery WHERE id = $1", d.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	return checkAffected(result)
+}
+
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, organizationId string, limit int, offset int) ([]webhook.Delivery, error) {
+	query := `
+    SELECT ` + deadLetterColumns + `
+    FROM webhook_dead_letter d
+    JOIN webhook_subscription s ON s.id = d.subscription_id
+    WHERE s.organization_id = $1
+    ORDER BY d.failed_at DESC, d.id
+    LIMIT $2 OFFSET $3`
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, organizationId, limit, offset)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer rows.Close()
+
+	var deliveries []webhook.Delivery
+	for rows.Next() {
+		d, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *WebhookRepository) GetDeadLetter(ctx context.Context, organizationId string, deliveryId string) (webhook.Delivery, error) {
+	query := `
+    SELECT ` + deadLetterColumns + `
+    FROM webhook_dead_letter d
+    JOIN webhook_subscription s ON s.id = d.subscription_id
+    WHERE s.organization_id = $1 AND d.id = $2`
+
+	d, err := scanDeadLetter(r.conn(ctx).QueryRowContext(ctx, query, organizationId, deliveryId))
+	if err != nil {
+		return d, mapError(err)
+	}
+
+	return d, nil
+}
+
+func (r *WebhookRepository) Requeue(ctx context.Context, d webhook.Delivery) error {
+	result, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM webhook_dead_letter WHERE id = $1", d.Id)
+	if err != nil {
+		return mapError(err)
+	}
+
+	if err = checkAffected(result); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO webhook_delivery (id, subscription_id, event_id, event_type, payload, attempts, next_attempt_at, last_error, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+
+	_, err = r.conn(ctx).ExecContext(ctx, query, d.Id, d.SubscriptionId, d.EventId, d.EventType, []byte(d.Payload), d.Attempts, d.NextAttemptAt, d.LastError, d.CreatedAt)
+	return mapError(err)
+}
+
+func scanSubscription(row scanner) (webhook.Subscription, error) {
+	var (
+		s          webhook.Subscription
+		eventTypes []string
+	)
+
+	err := row.Scan(&s.Id, &s.OrganizationId, &s.Url, &s.Secret, pq.Array(&eventTypes), &s.CreatedAt)
+
+	s.EventTypes = make([]event.Type, len(eventTypes))
+	for i, eventType := range eventTypes {
+		s.EventTypes[i] = event.Type(eventType)
+	}
+
+	return s, err
+}
+
+func scanSubscriptions(rows *sql.Rows) ([]webhook.Subscription, error) {
+	var subscriptions []webhook.Subscription
+
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func scanDeadLetter(row scanner) (webhook.Delivery, error) {
+	var d webhook.Delivery
+
+	err := row.Scan(&d.Id, &d.SubscriptionId, &d.EventId, &d.EventType, &d.Payload, &d.Attempts, &d.LastError, &d.CreatedAt, &d.FailedAt)
+
+	return d, err
+}
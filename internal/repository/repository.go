@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrInvalidReference = errors.New("invalid reference")
+	ErrConflict         = errors.New("concurrent modification")
+	ErrDuplicate        = errors.New("duplicate")
+)
+
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// Transactor starts a serializable transaction. Repository calls made with the
+// returned context run inside that transaction.
+type Transactor interface {
+	BeginTx(ctx context.Context) (context.Context, Tx, error)
+}
+
+// Locker takes locks that are held until the surrounding transaction ends and
+// coordinate work between several instances of the service.
+type Locker interface {
+	TryLock(ctx context.Context, key string) (bool, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 10 * time.Millisecond
+)
+
+// InTx runs fn as a single unit of work. The transaction is committed when fn
+// returns nil and rolled back otherwise, so callers can return early freely.
+// Serialization failures and deadlocks reported as ErrConflict restart fn from
+// scratch, which means fn must not have side effects outside the transaction.
+// Other errors, ErrDuplicate included, are returned without a retry.
+func InTx(ctx context.Context, transactor Transactor, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = runTx(ctx, transactor, fn)
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(time.Duration(attempt) * txRetryDelay):
+		}
+	}
+
+	return err
+}
+
+func runTx(ctx context.Context, transactor Transactor, fn func(ctx context.Context) error) error {
+	txCtx, tx, err := transactor.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(txCtx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
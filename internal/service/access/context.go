@@ -0,0 +1,24 @@
+package access
+
+import "context"
+
+type organizationKey struct{}
+
+// WithOrganization limits the checks made with ctx to organizationId. Requests
+// authenticated with an organization API key carry it, so the key can't reach
+// other organizations its creator is responsible for.
+func WithOrganization(ctx context.Context, organizationId string) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationId)
+}
+
+// WithoutOrganization lifts the limit of WithOrganization for checks about
+// users other than the caller.
+func WithoutOrganization(ctx context.Context) context.Context {
+	return context.WithValue(ctx, organizationKey{}, nil)
+}
+
+// OrganizationFrom returns the organization set with WithOrganization, if any.
+func OrganizationFrom(ctx context.Context) (string, bool) {
+	organizationId, ok := ctx.Value(organizationKey{}).(string)
+	return organizationId, ok
+}
@@ -0,0 +1,74 @@
+package access
+
+import "slices"
+
+type Role string
+type Permission string
+
+const (
+	RoleOwner              Role = "Owner"
+	RoleProcurementManager Role = "ProcurementManager"
+	RoleEvaluator          Role = "Evaluator"
+	RoleViewer             Role = "Viewer"
+)
+
+const (
+	PermissionTenderView    Permission = "tender:view"
+	PermissionTenderCreate  Permission = "tender:create"
+	PermissionTenderEdit    Permission = "tender:edit"
+	PermissionTenderPublish Permission = "tender:publish"
+	PermissionBidView       Permission = "bid:view"
+	PermissionBidSubmit     Permission = "bid:submit"
+	PermissionBidDecide     Permission = "bid:decide"
+	PermissionBidFeedback   Permission = "bid:feedback"
+	// PermissionOrganizationManage covers webhooks and API keys.
+	PermissionOrganizationManage Permission = "organization:manage"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleOwner: {
+		PermissionTenderView, PermissionTenderCreate, PermissionTenderEdit, PermissionTenderPublish,
+		PermissionBidView, PermissionBidSubmit, PermissionBidDecide, PermissionBidFeedback,
+		PermissionOrganizationManage,
+	},
+	RoleProcurementManager: {
+		PermissionTenderView, PermissionTenderCreate, PermissionTenderEdit, PermissionTenderPublish,
+		PermissionBidView, PermissionBidSubmit, PermissionBidDecide, PermissionBidFeedback,
+	},
+	RoleEvaluator: {
+		PermissionTenderView,
+		PermissionBidView, PermissionBidDecide, PermissionBidFeedback,
+	},
+	RoleViewer: {
+		PermissionTenderView,
+		PermissionBidView,
+	},
+}
+
+func ValidRole(role Role) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func (r Role) Has(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// rolesWith returns the roles granting permission.
+func rolesWith(permission Permission) []Role {
+	var roles []Role
+	for _, role := range []Role{RoleOwner, RoleProcurementManager, RoleEvaluator, RoleViewer} {
+		if role.Has(permission) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// Authorship lists who a user may act as when authoring bids: themself, unless
+// UserId is empty, and the organizations in OrganizationIds.
+type Authorship struct {
+	UserId          string
+	OrganizationIds []string
+}
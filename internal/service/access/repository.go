@@ -0,0 +1,14 @@
+package access
+
+import "context"
+
+type Repository interface {
+	UserExists(ctx context.Context, username string) (bool, error)
+	UserId(ctx context.Context, username string) (string, error)
+	// Role returns the role of username in the organization, or
+	// repository.ErrNotFound when the user is not a member.
+	Role(ctx context.Context, username string, organizationId string) (Role, error)
+	CountMembers(ctx context.Context, organizationId string, roles []Role) (int, error)
+	// Organizations returns the organizations where username has one of roles.
+	Organizations(ctx context.Context, username string, roles []Role) ([]string, error)
+}
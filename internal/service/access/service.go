@@ -0,0 +1,101 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"slices"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func (s *Service) UserExists(ctx context.Context, username string) (bool, error) {
+	return s.repo.UserExists(ctx, username)
+}
+
+func (s *Service) UserId(ctx context.Context, username string) (string, error) {
+	return s.repo.UserId(ctx, username)
+}
+
+// Can is the access policy: it reports whether username's role in the
+// organization grants permission.
+func (s *Service) Can(ctx context.Context, username string, permission Permission, organizationId string) (bool, error) {
+	if restricted, ok := OrganizationFrom(ctx); ok && restricted != organizationId {
+		return false, nil
+	}
+
+	role, err := s.repo.Role(ctx, username, organizationId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return role.Has(permission), nil
+}
+
+// IsAuthor reports whether username may act as the author of a bid: the user
+// itself, or a member allowed to submit bids for the organization.
+func (s *Service) IsAuthor(ctx context.Context, username string, authorType string, authorId string) (bool, error) {
+	if authorType == "Organization" {
+		return s.Can(ctx, username, PermissionBidSubmit, authorId)
+	}
+
+	if _, restricted := OrganizationFrom(ctx); restricted || authorType != "User" {
+		return false, nil
+	}
+
+	userId, err := s.repo.UserId(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return userId == authorId, nil
+}
+
+// Authorship lists every author username may act as under the rules of
+// IsAuthor, so lists of a user's bids follow the same policy.
+func (s *Service) Authorship(ctx context.Context, username string) (Authorship, error) {
+	organizationIds, err := s.repo.Organizations(ctx, username, rolesWith(PermissionBidSubmit))
+	if err != nil {
+		return Authorship{}, err
+	}
+
+	if restricted, ok := OrganizationFrom(ctx); ok {
+		organizationIds = slices.DeleteFunc(organizationIds, func(organizationId string) bool {
+			return organizationId != restricted
+		})
+
+		return Authorship{OrganizationIds: organizationIds}, nil
+	}
+
+	userId, err := s.repo.UserId(ctx, username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return Authorship{}, err
+	}
+
+	return Authorship{UserId: userId, OrganizationIds: organizationIds}, nil
+}
+
+// Quorum is the number of approvals a bid needs: three, or every member
+// allowed to decide in smaller organizations.
+func (s *Service) Quorum(ctx context.Context, organizationId string) (int, error) {
+	deciders, err := s.repo.CountMembers(ctx, organizationId, rolesWith(PermissionBidDecide))
+	if err != nil {
+		return 0, err
+	}
+
+	return min(3, deciders), nil
+}
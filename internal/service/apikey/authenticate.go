@@ -0,0 +1,41 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"strings"
+	"time"
+)
+
+// Authenticate resolves a key presented by a client. The last-used timestamp
+// is refreshed at most once per touchInterval to keep reads cheap.
+func (s *Service) Authenticate(ctx context.Context, secret string) (Principal, error) {
+	if !strings.HasPrefix(secret, keyPrefix) {
+		return Principal{}, errs.Unauthorized("Invalid API key")
+	}
+
+	key, err := s.repo.GetKeyByHash(ctx, hashKey(secret))
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && key.RevokedAt != nil) {
+		return Principal{}, errs.Unauthorized("Invalid API key")
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+
+	now := time.Now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.touchInterval {
+		if err = s.repo.TouchKey(ctx, key.Id.String(), now); err != nil {
+			return Principal{}, err
+		}
+	}
+
+	return Principal{
+		KeyId:          key.Id,
+		OrganizationId: key.OrganizationId,
+		UserId:         key.CreatorId,
+		Username:       key.CreatorUsername,
+		Scopes:         key.Scopes,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/google/uuid"
+	"slices"
+)
+
+const (
+	keyPrefix    = "tk_"
+	prefixLength = len(keyPrefix) + 8
+)
+
+func generateKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + hex.EncodeToString(raw), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func validateId(id string, name string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errs.Validation("Invalid " + name)
+	}
+
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" || len(name) > 100 {
+		return errs.Validation("Name must be 1 to 100 characters long")
+	}
+
+	return nil
+}
+
+func validateScopes(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return errs.Validation("At least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		switch scope {
+		case ScopeTendersRead, ScopeTendersWrite, ScopeBidsRead, ScopeBidsWrite:
+		default:
+			return errs.Validation("Unsupported scope " + string(scope))
+		}
+	}
+
+	return nil
+}
+
+func normalizeScopes(scopes []Scope) []Scope {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+
+	return slices.Compact(scopes)
+}
+
+func validatePagination(limit int, offset int) error {
+	if limit < 0 || limit > 50 {
+		return errs.Validation("Invalid limit value")
+	}
+
+	if offset < 0 {
+		return errs.Validation("Invalid offset value")
+	}
+
+	return nil
+}
+
+// checkManager allows only members that manage the organization to change its
+// API keys.
+func (s *Service) checkManager(ctx context.Context, username string, organizationId string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
+	}
+
+	userExists, err := s.access.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
+	}
+
+	allowed, err := s.access.Can(ctx, username, access.PermissionOrganizationManage, organizationId)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errs.Forbidden("User is not allowed to manage the organization")
+	}
+
+	return nil
+}
+
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("API key was modified concurrently, retry the request")
+	}
+
+	return err
+}
@@ -0,0 +1,121 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Create issues a new API key for the organization on behalf of username.
+func (s *Service) Create(ctx context.Context, organizationId string, username string, key Key) (CreatedKey, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return CreatedKey{}, err
+	}
+
+	if err := validateName(key.Name); err != nil {
+		return CreatedKey{}, err
+	}
+
+	if err := validateScopes(key.Scopes); err != nil {
+		return CreatedKey{}, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return CreatedKey{}, err
+	}
+
+	creatorId, err := s.access.UserId(ctx, username)
+	if err != nil {
+		return CreatedKey{}, err
+	}
+
+	secret, err := generateKey()
+	if err != nil {
+		return CreatedKey{}, err
+	}
+
+	created, err := s.repo.CreateKey(ctx, Key{
+		OrganizationId:  uuid.MustParse(organizationId),
+		Name:            key.Name,
+		Scopes:          normalizeScopes(key.Scopes),
+		Prefix:          secret[:prefixLength],
+		CreatorId:       creatorId,
+		CreatorUsername: username,
+		KeyHash:         hashKey(secret),
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return CreatedKey{}, errs.Conflict("API key could not be created, retry the request")
+	}
+	if err != nil {
+		return CreatedKey{}, err
+	}
+
+	return CreatedKey{Key: created, Secret: secret}, nil
+}
+
+func (s *Service) List(ctx context.Context, organizationId string, username string, limit int, offset int) ([]Key, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return nil, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.ListKeys(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Key{}, keys...), nil
+}
+
+// Revoke disables a key for good. Revoking a revoked key is a no-op.
+func (s *Service) Revoke(ctx context.Context, organizationId string, keyId string, username string) (Key, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Key{}, err
+	}
+
+	if err := validateId(keyId, "keyId"); err != nil {
+		return Key{}, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return Key{}, err
+	}
+
+	var revoked Key
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		key, err := s.repo.GetKey(ctx, keyId)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && key.OrganizationId.String() != organizationId) {
+			return errs.NotFound("API key not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if key.RevokedAt == nil {
+			at := time.Now().UTC()
+			if err = s.repo.RevokeKey(ctx, keyId, at); err != nil {
+				return err
+			}
+			key.RevokedAt = &at
+		}
+
+		revoked = key
+
+		return nil
+	})
+	if err != nil {
+		return Key{}, err
+	}
+
+	return revoked, nil
+}
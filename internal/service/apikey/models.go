@@ -0,0 +1,51 @@
+package apikey
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeTendersRead  Scope = "tenders:read"
+	ScopeTendersWrite Scope = "tenders:write"
+	ScopeBidsRead     Scope = "bids:read"
+	ScopeBidsWrite    Scope = "bids:write"
+)
+
+// Key is an organization API key. Requests made with it act on behalf of the
+// employee that created it, limited to the organization and the scopes.
+type Key struct {
+	Id              uuid.UUID  `json:"id"`
+	OrganizationId  uuid.UUID  `json:"organizationId"`
+	Name            string     `json:"name" binding:"required"`
+	Scopes          []Scope    `json:"scopes" binding:"required"`
+	Prefix          string     `json:"prefix"`
+	CreatorId       string     `json:"-"`
+	CreatorUsername string     `json:"creatorUsername"`
+	KeyHash         string     `json:"-"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	LastUsedAt      *time.Time `json:"lastUsedAt"`
+	RevokedAt       *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreatedKey is returned once on creation, the only time the key itself is
+// shown.
+type CreatedKey struct {
+	Key
+	Secret string `json:"key"`
+}
+
+type Principal struct {
+	KeyId          uuid.UUID
+	OrganizationId uuid.UUID
+	UserId         string
+	Username       string
+	Scopes         []Scope
+}
+
+func (p Principal) Allows(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope)
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"time"
+)
+
+type Repository interface {
+	repository.Transactor
+	CreateKey(ctx context.Context, key Key) (Key, error)
+	GetKey(ctx context.Context, keyId string) (Key, error)
+	GetKeyByHash(ctx context.Context, keyHash string) (Key, error)
+	ListKeys(ctx context.Context, organizationId string, limit int, offset int) ([]Key, error)
+	RevokeKey(ctx context.Context, keyId string, at time.Time) error
+	TouchKey(ctx context.Context, keyId string, at time.Time) error
+}
@@ -0,0 +1,20 @@
+package apikey
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"time"
+)
+
+type Service struct {
+	repo          Repository
+	access        *access.Service
+	touchInterval time.Duration
+}
+
+func NewService(repo Repository, access *access.Service) *Service {
+	return &Service{
+		repo:          repo,
+		access:        access,
+		touchInterval: time.Minute,
+	}
+}
@@ -0,0 +1,214 @@
+package apikey_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/apikey"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fixture struct {
+	router         *gin.Engine
+	store          *memory.Store
+	organizationId string
+	otherId        string
+}
+
+func newFixture() fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	otherId := store.AddOrganization("other")
+	ownerId := store.AddEmployee("owner")
+	store.AddResponsible(organizationId, ownerId)
+	store.AddResponsible(otherId, ownerId)
+
+	accessService := access.NewService(memory.NewAccessRepository(store))
+	tenders := memory.NewTenderRepository(store)
+	outbox := memory.NewOutboxRepository(store)
+	tenderService := tender.NewService(tenders, accessService, outbox)
+	bidService := bid.NewService(memory.NewBidRepository(store), tenders, tenderService, accessService, outbox)
+	service := apikey.NewService(memory.NewApiKeyRepository(store), accessService)
+	cmd := commands.NewCommander(tenderService, bidService, nil, nil, nil, nil, service, nil, page.NewCodec([]byte("test-secret")))
+
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(cmd.Authenticate)
+	router.GET("/organizations/:organizationId/api-keys", cmd.ListApiKeys)
+	router.POST("/organizations/:organizationId/api-keys", cmd.AddApiKey)
+	router.DELETE("/organizations/:organizationId/api-keys/:keyId", cmd.RevokeApiKey)
+	router.POST("/tenders/new", cmd.AddTender)
+	router.GET("/tenders/my", cmd.ListMyTenders)
+	router.GET("/bids/my", cmd.ListMy)
+
+	return fixture{router: router, store: store, organizationId: organizationId, otherId: otherId}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, key string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := httptest.NewRequest(method, target, &payload)
+	if key != "" {
+		request.Header.Set("X-API-Key", key)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, request)
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func (f fixture) createKey(t *testing.T, scopes ...apikey.Scope) apikey.CreatedKey {
+	t.Helper()
+
+	var created apikey.CreatedKey
+	code := f.do(t, http.MethodPost, "/organizations/"+f.organizationId+"/api-keys?username=owner", "", gin.H{"name": "ERP", "scopes": scopes}, &created)
+	if code != http.StatusCreated {
+		t.Fatalf("create code = %d, want %d", code, http.StatusCreated)
+	}
+	if created.Secret == "" || created.Prefix == "" {
+		t.Fatalf("created key = %+v, want secret and prefix", created)
+	}
+
+	return created
+}
+
+func (f fixture) addTender(t *testing.T, key string, organizationId string) int {
+	t.Helper()
+
+	return f.do(t, http.MethodPost, "/tenders/new", key, gin.H{
+		"name":            "Tender",
+		"description":     "Created by the ERP",
+		"serviceType":     tender.TenderServiceTypeDelivery,
+		"organizationId":  organizationId,
+		"creatorUsername": "owner",
+	}, nil)
+}
+
+func TestKeyActsWithinOrganizationAndScopes(t *testing.T) {
+	f := newFixture()
+	writer := f.createKey(t, apikey.ScopeTendersWrite)
+	reader := f.createKey(t, apikey.ScopeTendersRead)
+
+	if code := f.addTender(t, writer.Secret, f.organizationId); code != http.StatusCreated {
+		t.Fatalf("add with key code = %d, want %d", code, http.StatusCreated)
+	}
+	if code := f.addTender(t, writer.Secret, f.otherId); code != http.StatusForbidden {
+		t.Fatalf("add for other organization code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.addTender(t, reader.Secret, f.organizationId); code != http.StatusForbidden {
+		t.Fatalf("add without scope code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodGet, "/tenders/my", reader.Secret, nil, nil); code != http.StatusOK {
+		t.Fatalf("read with key code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodGet, "/organizations/"+f.organizationId+"/api-keys", writer.Secret, nil, nil); code != http.StatusForbidden {
+		t.Fatalf("admin with key code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	var keys []apikey.Key
+	if code := f.do(t, http.MethodGet, "/organizations/"+f.organizationId+"/api-keys?username=owner", "", nil, &keys); code != http.StatusOK {
+		t.Fatalf("list code = %d, want %d", code, http.StatusOK)
+	}
+	if len(keys) != 2 || keys[0].LastUsedAt == nil || keys[0].CreatorUsername != "owner" {
+		t.Fatalf("keys = %+v, want two used keys created by owner", keys)
+	}
+}
+
+func TestKeyListsOnlyItsOrganization(t *testing.T) {
+	f := newFixture()
+	reader := f.createKey(t, apikey.ScopeTendersRead, apikey.ScopeBidsRead)
+
+	tenders := memory.NewTenderRepository(f.store)
+	bids := memory.NewBidRepository(f.store)
+	for _, organizationId := range []string{f.organizationId, f.otherId} {
+		created, err := tenders.Create(context.Background(), tender.Tender{
+			Name:            "Tender",
+			Status:          tender.TenderStatusPublished,
+			ServiceType:     tender.TenderServiceTypeDelivery,
+			Version:         1,
+			OrganizationId:  uuid.MustParse(organizationId),
+			CreatorUsername: "owner",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = bids.Create(context.Background(), bid.Bid{
+			Name:       "Offer",
+			Status:     bid.BidStatusCreated,
+			TenderId:   created.Id,
+			AuthorType: bid.BidAuthorOrganization,
+			AuthorId:   organizationId,
+			Version:    1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var myTenders []tender.Tender
+	if code := f.do(t, http.MethodGet, "/tenders/my", reader.Secret, nil, &myTenders); code != http.StatusOK {
+		t.Fatalf("tenders code = %d, want %d", code, http.StatusOK)
+	}
+	if len(myTenders) != 1 || myTenders[0].OrganizationId.String() != f.organizationId {
+		t.Fatalf("tenders = %+v, want only the key's organization", myTenders)
+	}
+
+	var myBids []bid.Bid
+	if code := f.do(t, http.MethodGet, "/bids/my", reader.Secret, nil, &myBids); code != http.StatusOK {
+		t.Fatalf("bids code = %d, want %d", code, http.StatusOK)
+	}
+	if len(myBids) != 1 || myBids[0].AuthorId != f.organizationId {
+		t.Fatalf("bids = %+v, want only the key's organization", myBids)
+	}
+}
+
+func TestRevokedKeyIsRejected(t *testing.T) {
+	f := newFixture()
+	created := f.createKey(t, apikey.ScopeTendersWrite)
+
+	if code := f.do(t, http.MethodDelete, "/organizations/"+f.otherId+"/api-keys/"+created.Id.String()+"?username=owner", "", nil, nil); code != http.StatusNotFound {
+		t.Fatalf("revoke from other organization code = %d, want %d", code, http.StatusNotFound)
+	}
+
+	var revoked apikey.Key
+	if code := f.do(t, http.MethodDelete, "/organizations/"+f.organizationId+"/api-keys/"+created.Id.String()+"?username=owner", "", nil, &revoked); code != http.StatusOK {
+		t.Fatalf("revoke code = %d, want %d", code, http.StatusOK)
+	}
+	if revoked.RevokedAt == nil {
+		t.Fatal("revoked key has no revokedAt")
+	}
+
+	if code := f.addTender(t, created.Secret, f.organizationId); code != http.StatusUnauthorized {
+		t.Fatalf("add with revoked key code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.addTender(t, "tk_unknown", f.organizationId); code != http.StatusUnauthorized {
+		t.Fatalf("add with unknown key code = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
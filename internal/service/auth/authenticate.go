@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"time"
+)
+
+// Authenticate verifies an access token.
+func (s *Service) Authenticate(accessToken string) (Principal, error) {
+	principal, err := parseToken(s.config.Secret, accessToken, time.Now())
+	if err != nil {
+		return Principal{}, errs.Unauthorized("Invalid or expired access token")
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return errs.Validation("Password must be 8 to 72 characters long")
+	}
+
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func checkPassword(account Account, password string) bool {
+	if account.PasswordHash == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)) == nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("Token was used concurrently, retry the request")
+	}
+
+	return err
+}
+
+// issueTokens returns a new access token and stores a new refresh token for
+// the account.
+func (s *Service) issueTokens(ctx context.Context, account Account) (Tokens, error) {
+	now := time.Now().UTC()
+	principal := Principal{UserId: account.UserId, Username: account.Username}
+
+	accessToken, err := signToken(s.config.Secret, principal, now, s.config.AccessTTL)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return Tokens{}, err
+	}
+	refreshToken := hex.EncodeToString(raw)
+
+	err = s.repo.CreateRefreshToken(ctx, RefreshToken{
+		UserId:    account.UserId,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(s.config.RefreshTTL),
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		return Tokens{}, errs.Conflict("Refresh token could not be issued, retry the request")
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.config.AccessTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+)
+
+func (s *Service) Login(ctx context.Context, credentials Credentials) (Tokens, error) {
+	account, err := s.repo.GetAccount(ctx, credentials.Username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return Tokens{}, err
+	}
+
+	if err != nil || !checkPassword(account, credentials.Password) {
+		return Tokens{}, errs.Unauthorized("Invalid username or password")
+	}
+
+	var tokens Tokens
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		tokens, err = s.issueTokens(ctx, account)
+		return err
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return tokens, nil
+}
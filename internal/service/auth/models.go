@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Principal is the employee a request is made on behalf of.
+type Principal struct {
+	UserId   string
+	Username string
+}
+
+type Credentials struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type PasswordChange struct {
+	CurrentPassword string `json:"currentPassword"`
+	Password        string `json:"password" binding:"required"`
+}
+
+type PasswordSetup struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type Tokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+}
+
+type Account struct {
+	UserId       string
+	Username     string
+	PasswordHash string
+}
+
+type RefreshToken struct {
+	Id        uuid.UUID
+	UserId    string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+type Config struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// AllowUsername keeps accepting the username query parameter from
+	// requests without a token while clients migrate to bearer tokens. It is
+	// off unless enabled explicitly.
+	AllowUsername bool
+	// SetupToken lets an administrator set the first password of employees
+	// that predate authentication. Empty disables it.
+	SetupToken string
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+)
+
+// SetPassword changes the password of the authenticated user username, who
+// has to confirm the current one. Employees without a password get their first
+// one through SetupPassword.
+func (s *Service) SetPassword(ctx context.Context, username string, change PasswordChange) error {
+	if username == "" {
+		return errs.Unauthorized("Bearer token is required")
+	}
+
+	if err := validatePassword(change.Password); err != nil {
+		return err
+	}
+
+	account, err := s.repo.GetAccount(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errs.Unauthorized("Unauthorized user")
+	}
+	if err != nil {
+		return err
+	}
+
+	if !checkPassword(account, change.CurrentPassword) {
+		return errs.Forbidden("Current password is wrong")
+	}
+
+	return s.storePassword(ctx, account, change.Password)
+}
+
+// SetupPassword sets the first password of an employee that predates
+// authentication. It needs the setup token from the configuration and refuses
+// accounts that already have a password.
+func (s *Service) SetupPassword(ctx context.Context, setupToken string, setup PasswordSetup) error {
+	if s.config.SetupToken == "" || subtle.ConstantTimeCompare([]byte(setupToken), []byte(s.config.SetupToken)) != 1 {
+		return errs.Unauthorized("Invalid setup token")
+	}
+
+	if err := validatePassword(setup.Password); err != nil {
+		return err
+	}
+
+	account, err := s.repo.GetAccount(ctx, setup.Username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errs.NotFound("Employee not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if account.PasswordHash != "" {
+		return errs.Conflict("Password is already set")
+	}
+
+	return s.storePassword(ctx, account, setup.Password)
+}
+
+func (s *Service) storePassword(ctx context.Context, account Account, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.SetPasswordHash(ctx, account.UserId, hash)
+}
+
+// HashPassword validates and hashes a password for an employee that does not
+// exist yet.
+func (s *Service) HashPassword(password string) (string, error) {
+	if err := validatePassword(password); err != nil {
+		return "", err
+	}
+
+	return hashPassword(password)
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"time"
+)
+
+// Refresh exchanges a refresh token for a new pair of tokens. Each refresh
+// token can be used once.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (Tokens, error) {
+	if refreshToken == "" {
+		return Tokens{}, errs.Unauthorized("Refresh token is required")
+	}
+
+	var tokens Tokens
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+
+		token, err := s.repo.GetRefreshToken(ctx, hashRefreshToken(refreshToken))
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && (token.RevokedAt != nil || !token.ExpiresAt.After(now))) {
+			return errs.Unauthorized("Invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.RevokeRefreshToken(ctx, token.Id.String(), now); err != nil {
+			return err
+		}
+
+		account, err := s.repo.GetAccountById(ctx, token.UserId)
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.Unauthorized("Invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(ctx, account)
+		return err
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return tokens, nil
+}
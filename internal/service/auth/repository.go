@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"time"
+)
+
+type Repository interface {
+	repository.Transactor
+	// GetAccount finds an employee by username. PasswordHash is empty until
+	// the employee sets a password.
+	GetAccount(ctx context.Context, username string) (Account, error)
+	GetAccountById(ctx context.Context, userId string) (Account, error)
+	SetPasswordHash(ctx context.Context, userId string, passwordHash string) error
+	CreateRefreshToken(ctx context.Context, token RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenId string, at time.Time) error
+}
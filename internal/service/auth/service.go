@@ -0,0 +1,19 @@
+package auth
+
+type Service struct {
+	repo   Repository
+	config Config
+}
+
+func NewService(repo Repository, config Config) *Service {
+	return &Service{
+		repo:   repo,
+		config: config,
+	}
+}
+
+// AllowsUsername reports whether requests without a token may still identify
+// their user with the username query parameter.
+func (s *Service) AllowsUsername() bool {
+	return s.config.AllowUsername
+}
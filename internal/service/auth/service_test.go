@@ -0,0 +1,181 @@
+package auth_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const password = "correct horse battery"
+
+type fixture struct {
+	router *gin.Engine
+}
+
+func newFixture(allowUsername bool) fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	store.AddResponsible(organizationId, store.AddEmployee("owner"))
+
+	tenderService := tender.NewService(memory.NewTenderRepository(store), access.NewService(memory.NewAccessRepository(store)), memory.NewOutboxRepository(store))
+	authService := auth.NewService(memory.NewAuthRepository(store), auth.Config{
+		Secret:        []byte("test-secret"),
+		AccessTTL:     time.Minute,
+		RefreshTTL:    time.Hour,
+		AllowUsername: allowUsername,
+		SetupToken:    "setup-token",
+	})
+	cmd := commands.NewCommander(tenderService, nil, nil, nil, nil, authService, nil, nil, page.NewCodec([]byte("test-secret")))
+
+	router := gin.New()
+	router.Use(cmd.Authenticate)
+	router.POST("/auth/login", cmd.Login)
+	router.POST("/auth/refresh", cmd.RefreshToken)
+	router.PUT("/auth/password", cmd.SetPassword)
+	router.PUT("/auth/password/setup", cmd.SetupPassword)
+	router.GET("/tenders/my", cmd.ListMyTenders)
+
+	return fixture{router: router}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, token string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := httptest.NewRequest(method, target, &payload)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, request)
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+// setup sets the first password of owner with setupToken.
+func (f fixture) setup(t *testing.T, setupToken string, newPassword string) int {
+	t.Helper()
+
+	body, err := json.Marshal(gin.H{"username": "owner", "password": newPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPut, "/auth/password/setup", bytes.NewReader(body))
+	request.Header.Set("X-Setup-Token", setupToken)
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, request)
+
+	return recorder.Code
+}
+
+func (f fixture) login(t *testing.T) auth.Tokens {
+	t.Helper()
+
+	var tokens auth.Tokens
+	if code := f.do(t, http.MethodPost, "/auth/login", "", gin.H{"username": "owner", "password": password}, &tokens); code != http.StatusOK {
+		t.Fatalf("login code = %d, want %d", code, http.StatusOK)
+	}
+
+	return tokens
+}
+
+func TestLoginIssuesWorkingAccessToken(t *testing.T) {
+	f := newFixture(true)
+
+	if code := f.do(t, http.MethodPost, "/auth/login", "", gin.H{"username": "owner", "password": password}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("login without password code = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if code := f.do(t, http.MethodPut, "/auth/password?username=owner", "", gin.H{"password": "hijacked password"}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("set password by username code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.setup(t, "wrong-token", "hijacked password"); code != http.StatusUnauthorized {
+		t.Fatalf("setup with wrong token code = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if code := f.setup(t, "setup-token", password); code != http.StatusNoContent {
+		t.Fatalf("setup password code = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := f.setup(t, "setup-token", "hijacked password"); code != http.StatusConflict {
+		t.Fatalf("setup again code = %d, want %d", code, http.StatusConflict)
+	}
+
+	if code := f.do(t, http.MethodPost, "/auth/login", "", gin.H{"username": "owner", "password": "wrong password"}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("wrong password code = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	tokens := f.login(t)
+
+	if code := f.do(t, http.MethodGet, "/tenders/my", tokens.AccessToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("authenticated code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodGet, "/tenders/my", tokens.AccessToken+"x", nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("tampered token code = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	if code := f.do(t, http.MethodPut, "/auth/password", tokens.AccessToken, gin.H{"currentPassword": "wrong password", "password": "new password"}, nil); code != http.StatusForbidden {
+		t.Fatalf("change without current password code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodPut, "/auth/password", tokens.AccessToken, gin.H{"currentPassword": password, "password": "new password"}, nil); code != http.StatusNoContent {
+		t.Fatalf("change password code = %d, want %d", code, http.StatusNoContent)
+	}
+}
+
+func TestRefreshTokenIsSingleUse(t *testing.T) {
+	f := newFixture(true)
+	f.setup(t, "setup-token", password)
+	tokens := f.login(t)
+
+	var refreshed auth.Tokens
+	if code := f.do(t, http.MethodPost, "/auth/refresh", "", gin.H{"refreshToken": tokens.RefreshToken}, &refreshed); code != http.StatusOK {
+		t.Fatalf("refresh code = %d, want %d", code, http.StatusOK)
+	}
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	if code := f.do(t, http.MethodPost, "/auth/refresh", "", gin.H{"refreshToken": tokens.RefreshToken}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.do(t, http.MethodGet, "/tenders/my", refreshed.AccessToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("refreshed token code = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestUsernameParameterNeedsCompatibilityMode(t *testing.T) {
+	f := newFixture(false)
+
+	if code := f.do(t, http.MethodGet, "/tenders/my?username=owner", "", nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("username parameter code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.do(t, http.MethodPut, "/auth/password?username=owner", "", gin.H{"password": password}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("set password code = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
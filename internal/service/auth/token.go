@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errInvalidToken = errors.New("invalid token")
+
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type claims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// signToken issues an HS256 JWT for the principal.
+func signToken(secret []byte, principal Principal, now time.Time, ttl time.Duration) (string, error) {
+	payload, err := json.Marshal(claims{
+		Subject:   principal.UserId,
+		Username:  principal.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + signature(secret, unsigned), nil
+}
+
+// parseToken verifies an HS256 JWT and returns its principal. Tokens with any
+// other header are rejected, so the algorithm can't be downgraded.
+func parseToken(secret []byte, token string, now time.Time) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return Principal{}, errInvalidToken
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(signature(secret, parts[0]+"."+parts[1]))) {
+		return Principal{}, errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Principal{}, errInvalidToken
+	}
+
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return Principal{}, errInvalidToken
+	}
+
+	if now.Unix() >= c.ExpiresAt {
+		return Principal{}, errInvalidToken
+	}
+
+	return Principal{UserId: c.Subject, Username: c.Username}, nil
+}
+
+func signature(secret []byte, unsigned string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,51 +1,48 @@
 package bid
 
 import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
 )
 
-func (s *Service) Add(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
-
-	var bid Bid
-	if err = ctx.ShouldBindJSON(&bid); err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid request data"})
-		return
-	}
-
-	bid, ok := insertBid(tx, ctx, bid)
-	if !ok {
-		return
+func (s *Service) Add(ctx context.Context, username string, bid Bid) (Bid, error) {
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
 	}
 
-	if !insertBidDiff(tx, ctx, bid) {
-		return
-	}
-
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
-
-	returningTender := Bid{
-		Id:          bid.Id,
-		Name:        bid.Name,
-		Description: bid.Description,
-		Status:      BidStatusCreated,
-		TenderId:    bid.TenderId,
-		AuthorType:  bid.AuthorType,
-		AuthorId:    bid.AuthorId,
-		Version:     1,
-		CreatedAt:   bid.CreatedAt,
+	var created Bid
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		err := s.checkTenderOpen(ctx, username, bid.TenderId.String())
+		if err != nil {
+			return err
+		}
+
+		allowed, err := s.access.IsAuthor(ctx, username, string(bid.AuthorType), bid.AuthorId)
+		if err != nil {
+			return err
+		}
+		if !allowed && bid.AuthorType == BidAuthorOrganization {
+			return errs.Forbidden("User is not allowed to submit bids on behalf of the organization")
+		}
+		if !allowed {
+			return errs.Forbidden("Bid can only be created on behalf of the user")
+		}
+
+		created, err = s.insertBid(ctx, bid)
+		if err != nil {
+			return err
+		}
+
+		if err = s.insertDiff(ctx, created); err != nil {
+			return err
+		}
+
+		return s.record(ctx, event.BidCreated, created, created)
+	})
+	if err != nil {
+		return Bid{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusCreated, returningTender)
+	return created, nil
 }
@@ -0,0 +1,61 @@
+package bid
+
+import "context"
+
+func (s *Service) Diff(ctx context.Context, bidId string, username string, from int, to int) (BidDiff, error) {
+	if err := validateBidId(bidId); err != nil {
+		return BidDiff{}, err
+	}
+
+	if err := validateVersion(from); err != nil {
+		return BidDiff{}, err
+	}
+
+	if err := validateVersion(to); err != nil {
+		return BidDiff{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return BidDiff{}, err
+	}
+
+	bid, err := s.getBidById(ctx, bidId)
+	if err != nil {
+		return BidDiff{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, bid); err != nil {
+		return BidDiff{}, err
+	}
+
+	fromBid, err := s.getBidByIdAndVersion(ctx, bidId, from)
+	if err != nil {
+		return BidDiff{}, err
+	}
+
+	toBid, err := s.getBidByIdAndVersion(ctx, bidId, to)
+	if err != nil {
+		return BidDiff{}, err
+	}
+
+	return BidDiff{From: from, To: to, Changes: diffBids(fromBid, toBid)}, nil
+}
+
+func diffBids(from Bid, to Bid) []FieldChange {
+	changes := []FieldChange{}
+
+	for _, field := range []struct {
+		name     string
+		from, to string
+	}{
+		{"name", from.Name, to.Name},
+		{"description", from.Description, to.Description},
+		{"status", string(from.Status), string(to.Status)},
+	} {
+		if field.from != field.to {
+			changes = append(changes, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+
+	return changes
+}
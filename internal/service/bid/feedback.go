@@ -0,0 +1,55 @@
+package bid
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+)
+
+func (s *Service) Feedback(ctx context.Context, bidId string, username string, feedback string) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
+	}
+
+	if err := validateFeedback(feedback); err != nil {
+		return Bid{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
+	}
+
+	userId, err := s.getUserId(ctx, username)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	var reviewed Bid
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		bid, err := s.getBidById(ctx, bidId)
+		if err != nil {
+			return err
+		}
+
+		if err = s.checkTenderPermission(ctx, username, access.PermissionBidFeedback, bid.TenderId.String()); err != nil {
+			return err
+		}
+
+		if bid.Status == BidStatusCreated {
+			return errs.Validation("Feedback can't be left on an unpublished bid")
+		}
+
+		if err = s.repo.CreateReview(ctx, bidId, userId, feedback); err != nil {
+			return err
+		}
+
+		reviewed = bid
+		return s.record(ctx, event.BidFeedbackSubmitted, bid, FeedbackSubmitted{Bid: bid, Feedback: feedback, Username: username})
+	})
+	if err != nil {
+		return Bid{}, err
+	}
+
+	return reviewed, nil
+}
@@ -0,0 +1,26 @@
+package bid
+
+import "context"
+
+// Get returns the current version of a bid to its author and to the members
+// of the tender organization.
+func (s *Service) Get(ctx context.Context, bidId string, username string) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
+	}
+
+	bid, err := s.getBidById(ctx, bidId)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, bid); err != nil {
+		return Bid{}, err
+	}
+
+	return bid, nil
+}
@@ -1,253 +1,349 @@
 package bid
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
 	"strconv"
+	"time"
 )
 
-func validateStatus(status string) error {
-	switch BidStatus(status) {
-	case BidStatusCreated, BidStatusPublished, BidStatusCancelled:
+func validateStatus(status BidStatus) error {
+	switch status {
+	case BidStatusCreated, BidStatusPublished, BidStatusCancelled, BidStatusApproved, BidStatusRejected, BidStatusNotSelected:
 		return nil
 	}
 
-	return errors.New("invalid status")
+	return errs.Validation("Invalid status")
 }
 
-func checkUserExistence(db *sql.DB, ctx *gin.Context, username string) bool {
-	var userExists bool
+func validateDecision(decision BidDecision) error {
+	switch decision {
+	case BidDecisionApproved, BidDecisionRejected:
+		return nil
+	}
 
-	query := `SELECT EXISTS(SELECT 1 FROM employee WHERE username = $1)`
+	return errs.Validation("Invalid decision")
+}
 
-	err := db.QueryRow(query, username).Scan(&userExists)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return false
+func validateBidId(bidId string) error {
+	if bidId == "" || len(bidId) > 100 {
+		return errs.Validation("Invalid bidId")
 	}
 
-	if !userExists {
-		ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"reason": "Unauthorized user"})
-		return false
+	return nil
+}
+
+func validateTenderId(tenderId string) error {
+	if tenderId == "" || len(tenderId) > 100 {
+		return errs.Validation("Invalid tenderId")
+	}
+
+	return nil
+}
+
+func validateVersion(version int) error {
+	if version < 1 {
+		return errs.Validation("Version must be >= 1")
 	}
 
-	return true
+	return nil
 }
 
-func checkTenderExistence(db *sql.DB, ctx *gin.Context, tenderId string) bool {
-	var tenderExists bool
+func validateFeedback(feedback string) error {
+	if feedback == "" || len([]rune(feedback)) > 1000 {
+		return errs.Validation("Invalid bidFeedback")
+	}
 
-	query := `SELECT EXISTS(SELECT 1 FROM tender WHERE id = $1)`
+	return nil
+}
 
-	err := db.QueryRow(query, tenderId).Scan(&tenderExists)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return false
+func validatePagination(limit int, offset int) error {
+	if limit < 0 || limit > 50 {
+		return errs.Validation("Invalid limit value")
 	}
 
-	if !tenderExists {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
-		return false
+	if offset < 0 {
+		return errs.Validation("Invalid offset value")
 	}
 
-	return true
+	return nil
 }
 
-func getAuthorId(db *sql.DB, ctx *gin.Context, username string) (string, bool) {
-	var authorId string
-
-	query := `SELECT id FROM employee WHERE username = $1`
+// validatePage checks a page request, which continues after a cursor or skips
+// an offset but not both.
+func validatePage(request page.Request) error {
+	if err := validatePagination(request.Limit, request.Offset); err != nil {
+		return err
+	}
 
-	err := db.QueryRow(query, username).Scan(&authorId)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"reason": "Unauthorized user"})
-		return "", false
+	if request.After != nil && request.Offset != 0 {
+		return errs.Validation("Use either offset or cursor")
 	}
 
-	return authorId, true
+	return nil
 }
 
-func extractBids(ctx *gin.Context, rows *sql.Rows) ([]Bid, bool) {
-	var bids []Bid
+func (s *Service) checkUserExistence(ctx context.Context, username string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
+	}
 
-	for rows.Next() {
-		var b Bid
-		err := rows.Scan(&b.Id, &b.Name, &b.Description, &b.Status, &b.TenderId, &b.AuthorType, &b.AuthorId, &b.Version, &b.CreatedAt)
-		if err != nil {
-			ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Bids not found"})
-			return nil, false
-		}
-		bids = append(bids, b)
+	userExists, err := s.access.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
 	}
 
-	return bids, true
+	return nil
 }
 
-func checkVersionAndUsername(tx *sql.Tx, ctx *gin.Context, version int, authorId string, bidId string) (int, bool) {
-	query := "SELECT version, author_id FROM bid WHERE id = $1"
+func (s *Service) checkTenderExistence(ctx context.Context, tenderId string) error {
+	_, err := s.tenders.Get(ctx, tenderId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return errs.NotFound("Tender not found")
+	}
 
-	var currentVersion int
-	var creatorId string
+	return err
+}
 
-	err := tx.QueryRowContext(ctx, query, bidId).Scan(&currentVersion, &creatorId)
+func (s *Service) getUserId(ctx context.Context, username string) (string, error) {
+	userId, err := s.access.UserId(ctx, username)
 	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
-		return 0, false
+		return "", errs.Unauthorized("Unauthorized user")
 	}
 
-	if version >= currentVersion {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "No such a version. Latest version is " + strconv.Itoa(currentVersion)})
-		return 0, false
-	}
+	return userId, nil
+}
 
-	if authorId != creatorId {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return 0, false
+func checkVersion(bid Bid, version int) error {
+	if version >= bid.Version {
+		return errs.Validation("No such a version. Latest version is " + strconv.Itoa(bid.Version))
 	}
 
-	return currentVersion, true
+	return nil
 }
 
-func insertBid(tx *sql.Tx, ctx *gin.Context, bid Bid) (Bid, bool) {
-	query := "INSERT INTO bid (name, description, status, tender_id, author_type, author_id, version) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at"
+func checkExpectedVersion(bid Bid, expectedVersion int) error {
+	if expectedVersion != 0 && bid.Version != expectedVersion {
+		return errs.PreconditionFailed("Bid was modified, current version is " + strconv.Itoa(bid.Version))
+	}
 
-	err := tx.QueryRowContext(ctx, query, bid.Name, bid.Description, BidStatusCreated, bid.TenderId, bid.AuthorType, bid.AuthorId, 1).Scan(&bid.Id, &bid.CreatedAt)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Failed to rollback: %v", rollbackErr)})
-			return bid, false
-		}
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid tenderId or authorType or authorId"})
-		return bid, false
+	return nil
+}
+
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("Bid was modified concurrently, retry the request")
 	}
 
-	return bid, true
+	return err
 }
 
-func insertBidDiff(tx *sql.Tx, ctx *gin.Context, bid Bid) bool {
-	query := "INSERT INTO bid_diff (id, name, description, status, tender_id, author_type, author_id, version, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
+func (s *Service) insertBid(ctx context.Context, bid Bid) (Bid, error) {
+	bid.Status = BidStatusCreated
+	bid.Version = 1
 
-	if bid.Status == "" {
-		bid.Status = BidStatusCreated
+	bid, err := s.repo.Create(ctx, bid)
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return bid, errs.Validation("Invalid tenderId or authorType or authorId")
 	}
 
-	_, err := tx.ExecContext(ctx, query, bid.Id, bid.Name, bid.Description, bid.Status, bid.AuthorType, bid.AuthorId, bid.Version+1, bid.CreatedAt)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Failed to rollback: %v", rollbackErr)})
-			return false
-		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return false
+	return bid, err
+}
+
+func (s *Service) saveBid(ctx context.Context, bid Bid) error {
+	if err := s.repo.Update(ctx, bid); err != nil {
+		return err
 	}
 
-	return true
+	return s.insertDiff(ctx, bid)
 }
 
-func getBidById(tx *sql.Tx, ctx *gin.Context, bidId string) (Bid, bool) {
-	query := "SELECT * FROM bid WHERE id = $1"
+func (s *Service) insertDiff(ctx context.Context, bid Bid) error {
+	err := s.repo.InsertDiff(ctx, bid)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return errs.Conflict("Bid version " + strconv.Itoa(bid.Version) + " is already recorded")
+	}
 
-	var bid Bid
+	return err
+}
 
-	err := tx.QueryRowContext(ctx, query, bidId).Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Bid not found"})
-		return bid, false
+func (s *Service) getBidById(ctx context.Context, bidId string) (Bid, error) {
+	bid, err := s.repo.Get(ctx, bidId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return bid, errs.NotFound("Bid not found")
 	}
 
-	return bid, true
+	return bid, err
 }
 
-func getBidByIdAndVersion(tx *sql.Tx, ctx *gin.Context, bidId string, version int) (Bid, bool) {
-	var bid Bid
+func (s *Service) getBidByIdAndVersion(ctx context.Context, bidId string, version int) (Bid, error) {
+	bid, err := s.repo.GetVersion(ctx, bidId, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return bid, errs.NotFound("Version not found")
+	}
 
-	query := "SELECT * FROM bid_diff WHERE id = $1 AND version = $2"
+	return bid, err
+}
 
-	err := tx.QueryRowContext(ctx, query, bidId, version).Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId, &bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+func (s *Service) isAuthor(ctx context.Context, username string, bid Bid) (bool, error) {
+	return s.access.IsAuthor(ctx, username, string(bid.AuthorType), bid.AuthorId)
+}
+
+func (s *Service) checkAuthor(ctx context.Context, username string, bid Bid) error {
+	author, err := s.isAuthor(ctx, username, bid)
 	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Version not found"})
-		return bid, false
+		return err
 	}
 
-	return bid, true
-}
+	if !author {
+		return errs.Forbidden("Wrong username")
+	}
 
-func getTenderId(ctx *gin.Context) (string, bool) {
-	tenderId := ctx.Param("tenderId")
+	return nil
+}
 
-	if tenderId == "" || len(tenderId) > 100 {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid tenderId"})
-		return "", false
+// canOnTender reports whether username has permission in the organization
+// that owns the tender.
+func (s *Service) canOnTender(ctx context.Context, username string, permission access.Permission, tenderId string) (bool, error) {
+	t, err := s.getTender(ctx, tenderId)
+	if err != nil {
+		return false, err
 	}
 
-	return tenderId, true
+	return s.access.Can(ctx, username, permission, t.OrganizationId.String())
 }
 
-func getUsername(ctx *gin.Context) (string, bool) {
-	username := ctx.Query("username")
+func (s *Service) checkTenderPermission(ctx context.Context, username string, permission access.Permission, tenderId string) error {
+	allowed, err := s.canOnTender(ctx, username, permission, tenderId)
+	if err != nil {
+		return err
+	}
 
-	if username == "" {
-		ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"reason": "Username is required"})
-		return "", false
+	if !allowed {
+		return errs.Forbidden("User has no " + string(permission) + " permission in the tender organization")
 	}
 
-	return username, true
+	return nil
 }
 
-func getLimit(ctx *gin.Context) (int, bool) {
-	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
-
-	if err != nil || limit < 0 || limit > 50 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit value"})
-		return 0, false
+func (s *Service) getTender(ctx context.Context, tenderId string) (tender.Tender, error) {
+	t, err := s.tenders.Get(ctx, tenderId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return t, errs.NotFound("Tender not found")
 	}
 
-	return limit, true
+	return t, err
 }
 
-func getOffset(ctx *gin.Context) (int, bool) {
-	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+func (s *Service) record(ctx context.Context, eventType event.Type, bid Bid, payload any) error {
+	t, err := s.getTender(ctx, bid.TenderId.String())
+	if err != nil {
+		return err
+	}
 
-	if err != nil || offset < 0 || offset > 1<<31-1 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset value"})
-		return 0, false
+	e, err := event.New(eventType, t.OrganizationId, t.Id, payload)
+	if err != nil {
+		return err
 	}
+	e.BidId = &bid.Id
+	e.AuthorType = string(bid.AuthorType)
+	e.AuthorId = bid.AuthorId
 
-	return offset, true
+	return s.events.Append(ctx, e)
 }
 
-func getBidId(ctx *gin.Context) (string, bool) {
-	bidId := ctx.Param("bidId")
+func (s *Service) closeTender(ctx context.Context, bid Bid) error {
+	t, err := s.getTender(ctx, bid.TenderId.String())
+	if err != nil {
+		return err
+	}
 
-	if bidId == "" || len(bidId) > 100 {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid bidId"})
-		return "", false
+	_, err = s.tenderService.Transition(ctx, t, tender.TenderStatusClosed, tender.ActorSystem)
+	return err
+}
+
+// checkTenderOpen allows bids on the tender to be placed, edited or published.
+// A tender that isn't published yet stays hidden from users who can't see it.
+func (s *Service) checkTenderOpen(ctx context.Context, username string, tenderId string) error {
+	t, err := s.getTender(ctx, tenderId)
+	if err != nil {
+		return err
 	}
 
-	return bidId, true
+	switch t.Status {
+	case tender.TenderStatusCreated:
+		visible, err := s.access.Can(ctx, username, access.PermissionTenderView, t.OrganizationId.String())
+		if err != nil {
+			return err
+		}
+		if !visible {
+			return errs.NotFound("Tender not found")
+		}
+
+		return errs.Conflict("Tender is not published yet, bids can't be placed or changed")
+	case tender.TenderStatusClosed:
+		return errs.Validation("Tender is closed, bids can't be placed or changed")
+	}
+
+	if t.BidDeadline != nil && !t.BidDeadline.After(time.Now()) {
+		return errs.Validation("Bid deadline has passed")
+	}
+
+	return nil
 }
 
-func getStatus(ctx *gin.Context) (string, bool) {
-	status := ctx.Query("status")
+func (s *Service) closeOpenBids(ctx context.Context, t tender.Tender) error {
+	bids, err := s.repo.ListByTenderStatus(ctx, t.Id.String(), BidStatusCreated, BidStatusPublished)
+	if err != nil {
+		return err
+	}
 
-	if err := validateStatus(status); status == "" || err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid status"})
-		return "", false
+	for _, bid := range bids {
+		status := BidStatusNotSelected
+		if bid.Status == BidStatusCreated {
+			status = BidStatusCancelled
+		}
+
+		if _, err = s.transition(ctx, bid, status, ActorSystem); err != nil {
+			return err
+		}
 	}
 
-	return status, true
+	return nil
 }
 
-func getVersion(ctx *gin.Context) (int, bool) {
-	version, err := strconv.Atoi(ctx.Param("version"))
+func (s *Service) checkVisible(ctx context.Context, username string, bid Bid) error {
+	author, err := s.isAuthor(ctx, username, bid)
+	if err != nil {
+		return err
+	}
+
+	if author {
+		return nil
+	}
 
-	if err != nil || version < 1 {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Version must be >= 1"})
-		return 0, false
+	allowed, err := s.canOnTender(ctx, username, access.PermissionBidView, bid.TenderId.String())
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return errs.Forbidden("Wrong username")
 	}
 
-	return version, true
+	return nil
+}
+
+func pageKey(bid Bid) page.Key {
+	return page.Key{Name: bid.Name, Id: bid.Id}
 }
@@ -1,51 +1,33 @@
 package bid
 
 import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
 )
 
-func (s *Service) ListMy(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	limit, ok := getLimit(ctx)
-	if !ok {
-		return
-	}
-
-	offset, ok := getOffset(ctx)
-	if !ok {
-		return
-	}
-
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
+func (s *Service) ListMy(ctx context.Context, username string, request page.Request) (page.Page[Bid], error) {
+	if err := validatePage(request); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	authorId, ok := getAuthorId(db, ctx, username)
-	if !ok {
-		return
+	authors, err := s.access.Authorship(ctx, username)
+	if err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	query := "SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at FROM bid WHERE author_id = $1 AND author_type = $2 ORDER BY name LIMIT $3 OFFSET $4"
-
-	rows, err := db.Query(query, authorId, BidAuthorUser, limit, offset)
+	bids, err := s.repo.ListByAuthor(ctx, authors, request.Lookahead())
 	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+		return page.Page[Bid]{}, err
 	}
-	defer rows.Close()
 
-	bids, ok := extractBids(ctx, rows)
-	if !ok {
-		return
+	total, err := s.repo.CountByAuthor(ctx, authors)
+	if err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, bids)
+	return page.New(bids, request.Limit, total, pageKey), nil
 }
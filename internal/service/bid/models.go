@@ -7,11 +7,15 @@ import (
 
 type BidStatus string
 type BidAuthor string
+type BidDecision string
 
 const (
-	BidStatusCreated   BidStatus = "Created"
-	BidStatusPublished BidStatus = "Published"
-	BidStatusCancelled BidStatus = "Cancelled"
+	BidStatusCreated     BidStatus = "Created"
+	BidStatusPublished   BidStatus = "Published"
+	BidStatusCancelled   BidStatus = "Cancelled"
+	BidStatusApproved    BidStatus = "Approved"
+	BidStatusRejected    BidStatus = "Rejected"
+	BidStatusNotSelected BidStatus = "NotSelected"
 )
 
 const (
@@ -19,6 +23,11 @@ const (
 	BidAuthorOrganization BidAuthor = "Organization"
 )
 
+const (
+	BidDecisionApproved BidDecision = "Approved"
+	BidDecisionRejected BidDecision = "Rejected"
+)
+
 type Bid struct {
 	Id          uuid.UUID `json:"id"`
 	Name        string    `json:"name" binding:"required"`
@@ -35,3 +44,33 @@ type BidPatch struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
+
+type BidReview struct {
+	Id          uuid.UUID `json:"id"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type DecisionMade struct {
+	Bid      Bid         `json:"bid"`
+	Decision BidDecision `json:"decision"`
+	Username string      `json:"username"`
+}
+
+type FeedbackSubmitted struct {
+	Bid      Bid    `json:"bid"`
+	Feedback string `json:"feedback"`
+	Username string `json:"username"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type BidDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
@@ -1,118 +1,58 @@
 package bid
 
 import (
-	"database/sql"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
-	"strings"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
 )
 
-func (s *Service) Patch(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	bidId, ok := getBidId(ctx)
-	if !ok {
-		return
-	}
-
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
-	}
-
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
-	}
-
-	authorId, ok := getAuthorId(db, ctx, username)
-	if !ok {
-		return
-	}
-
-	var bidPatch BidPatch
-	if err := ctx.ShouldBindJSON(&bidPatch); err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
-		return
-	}
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+func (s *Service) Patch(ctx context.Context, bidId string, username string, bidPatch BidPatch, expectedVersion int) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
 	}
 
-	bid, ok := getBidById(tx, ctx, bidId)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
 	}
 
-	if authorId != bid.AuthorId {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return
-	}
-
-	changes := make(map[string]interface{})
-	bidDiffValues := make(map[string]interface{})
-
-	if bidPatch.Name != "" {
-		changes["name"] = bidPatch.Name
-		bidDiffValues["name"] = bidPatch.Name
-		bid.Name = bidPatch.Name
-	}
-
-	if bidPatch.Description != "" {
-		changes["description"] = bidPatch.Description
-		bidDiffValues["description"] = bidPatch.Description
-		bid.Description = bidPatch.Description
-	}
-
-	changes["version"] = bid.Version + 1
+	var updatedBid Bid
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		bid, err := s.getBidById(ctx, bidId)
+		if err != nil {
+			return err
+		}
 
-	var updates []string
-	var params []interface{}
-	paramCounter := 1
+		if err = checkExpectedVersion(bid, expectedVersion); err != nil {
+			return err
+		}
 
-	for column, value := range changes {
-		updates = append(updates, fmt.Sprintf("%s = $%d", column, paramCounter))
-		params = append(params, value)
-		paramCounter++
-	}
+		if err = s.checkAuthor(ctx, username, bid); err != nil {
+			return err
+		}
 
-	queryUpdate := fmt.Sprintf("UPDATE bid SET %s WHERE id = $%d", strings.Join(updates, ", "), paramCounter)
+		if err = s.checkTenderOpen(ctx, username, bid.TenderId.String()); err != nil {
+			return err
+		}
 
-	params = append(params, bidId)
+		if bidPatch.Name != "" {
+			bid.Name = bidPatch.Name
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, params...)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("err: %v, rollbackErr: %v", err, rollbackErr)})
-			return
+		if bidPatch.Description != "" {
+			bid.Description = bidPatch.Description
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertBidDiff(tx, ctx, bid) {
-		return
-	}
+		updatedBid = bid
+		updatedBid.Version = bid.Version + 1
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = s.saveTransition(ctx, bid.Status, updatedBid, ActorAuthor); err != nil {
+			return err
+		}
 
-	returningBid := Bid{
-		Id:          bid.Id,
-		Name:        bid.Name,
-		Description: bid.Description,
-		Status:      bid.Status,
-		TenderId:    bid.TenderId,
-		AuthorType:  bid.AuthorType,
-		AuthorId:    bid.AuthorId,
-		Version:     bid.Version + 1,
-		CreatedAt:   bid.CreatedAt,
+		return s.record(ctx, event.BidUpdated, updatedBid, updatedBid)
+	})
+	if err != nil {
+		return Bid{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningBid)
+	return updatedBid, nil
 }
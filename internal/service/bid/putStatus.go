@@ -1,92 +1,55 @@
 package bid
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
 )
 
-func (s *Service) PutStatus(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	bidId, ok := getBidId(ctx)
-	if !ok {
-		return
-	}
-
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
-	}
-
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
-	}
-
-	authorId, ok := getAuthorId(db, ctx, username)
-	if !ok {
-		return
-	}
-
-	newStatus, ok := getStatus(ctx)
-	if !ok {
-		return
-	}
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+func (s *Service) PutStatus(ctx context.Context, bidId string, username string, newStatus BidStatus, expectedVersion int) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
 	}
 
-	bid, ok := getBidById(tx, ctx, bidId)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
 	}
 
-	if newStatus == string(bid.Status) {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": fmt.Sprintf("Status is already %v", newStatus)})
-		return
+	if err := validateStatus(newStatus); err != nil {
+		return Bid{}, err
 	}
 
-	if authorId != bid.AuthorId {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return
-	}
+	var updatedBid Bid
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		bid, err := s.getBidById(ctx, bidId)
+		if err != nil {
+			return err
+		}
 
-	queryUpdate := "UPDATE bid SET status = $1, version = $2 WHERE id = $3"
+		if err = checkExpectedVersion(bid, expectedVersion); err != nil {
+			return err
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, newStatus, bid.Version+1, bidId)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("err: %v, rollbackErr: %v", err, rollbackErr)})
-			return
+		if newStatus == bid.Status {
+			return errs.Validation(fmt.Sprintf("Status is already %v", newStatus))
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertBidDiff(tx, ctx, bid) {
-		return
-	}
+		if err = s.checkAuthor(ctx, username, bid); err != nil {
+			return err
+		}
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if newStatus == BidStatusPublished {
+			if err = s.checkTenderOpen(ctx, username, bid.TenderId.String()); err != nil {
+				return err
+			}
+		}
 
-	returningBid := Bid{
-		Id:          bid.Id,
-		Name:        bid.Name,
-		Description: bid.Description,
-		Status:      BidStatus(newStatus),
-		TenderId:    bid.TenderId,
-		AuthorType:  bid.AuthorType,
-		AuthorId:    bid.AuthorId,
-		Version:     bid.Version + 1,
-		CreatedAt:   bid.CreatedAt,
+		updatedBid, err = s.transition(ctx, bid, newStatus, ActorAuthor)
+		return err
+	})
+	if err != nil {
+		return Bid{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningBid)
+	return updatedBid, nil
 }
@@ -0,0 +1,30 @@
+package bid
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+)
+
+type BidRepository interface {
+	repository.Transactor
+	Create(ctx context.Context, bid Bid) (Bid, error)
+	Get(ctx context.Context, bidId string) (Bid, error)
+	GetVersion(ctx context.Context, bidId string, version int) (Bid, error)
+	ListVersions(ctx context.Context, bidId string, limit int, offset int) ([]Bid, error)
+	Update(ctx context.Context, bid Bid) error
+	InsertDiff(ctx context.Context, bid Bid) error
+	// ListByAuthor and ListByTender order bids by name and id. Bids by any of
+	// authors count as authored.
+	ListByAuthor(ctx context.Context, authors access.Authorship, request page.Request) ([]Bid, error)
+	CountByAuthor(ctx context.Context, authors access.Authorship) (int, error)
+	ListByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool, request page.Request) ([]Bid, error)
+	CountByTender(ctx context.Context, tenderId string, authors access.Authorship, withPublished bool) (int, error)
+	ListByTenderStatus(ctx context.Context, tenderId string, statuses ...BidStatus) ([]Bid, error)
+	HasAuthoredBids(ctx context.Context, tenderId string, authors access.Authorship) (bool, error)
+	SaveDecision(ctx context.Context, bidId string, userId string, decision BidDecision) error
+	CountDecisions(ctx context.Context, bidId string, decision BidDecision) (int, error)
+	CreateReview(ctx context.Context, bidId string, userId string, description string) error
+	ListReviews(ctx context.Context, authors access.Authorship, limit int, offset int) ([]BidReview, error)
+}
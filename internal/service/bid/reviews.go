@@ -0,0 +1,58 @@
+package bid
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+)
+
+func (s *Service) Reviews(ctx context.Context, tenderId string, authorUsername string, requesterUsername string, limit int, offset int) ([]BidReview, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return nil, err
+	}
+
+	if authorUsername == "" {
+		return nil, errs.Validation("Author username is required")
+	}
+
+	if requesterUsername == "" {
+		return nil, errs.Unauthorized("Requester username is required")
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUserExistence(ctx, requesterUsername); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderExistence(ctx, tenderId); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTenderPermission(ctx, requesterUsername, access.PermissionBidView, tenderId); err != nil {
+		return nil, err
+	}
+
+	authors, err := s.access.Authorship(access.WithoutOrganization(ctx), authorUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	hasBids, err := s.repo.HasAuthoredBids(ctx, tenderId, authors)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasBids {
+		return nil, errs.NotFound("Author has no bids for this tender")
+	}
+
+	reviews, err := s.repo.ListReviews(ctx, authors, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]BidReview{}, reviews...), nil
+}
@@ -1,86 +1,63 @@
 package bid
 
 import (
-	"database/sql"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
 )
 
-func (s *Service) Rollback(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	bidId, ok := getBidId(ctx)
-	if !ok {
-		return
-	}
-
-	newVersion, ok := getVersion(ctx)
-	if !ok {
-		return
+func (s *Service) Rollback(ctx context.Context, bidId string, username string, version int, expectedVersion int) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
 	}
 
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
+	if err := validateVersion(version); err != nil {
+		return Bid{}, err
 	}
 
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
 	}
 
-	authorId, ok := getAuthorId(db, ctx, username)
-	if !ok {
-		return
-	}
+	var updatedBid Bid
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		bid, err := s.getBidById(ctx, bidId)
+		if err != nil {
+			return err
+		}
 
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = checkExpectedVersion(bid, expectedVersion); err != nil {
+			return err
+		}
 
-	currentVersion, ok := checkVersionAndUsername(tx, ctx, newVersion, authorId, bidId)
-	if !ok {
-		return
-	}
+		if err = checkVersion(bid, version); err != nil {
+			return err
+		}
 
-	newBid, ok := getBidByIdAndVersion(tx, ctx, bidId, newVersion)
-	if !ok {
-		return
-	}
+		if err = s.checkAuthor(ctx, username, bid); err != nil {
+			return err
+		}
 
-	queryUpdate := "UPDATE bid SET name = $1, description = $2, status = $3, tender_id = $4, author_type = $5, author_id = $6, version = $7, created_at = $8 WHERE id = $9"
+		if err = s.checkTenderOpen(ctx, username, bid.TenderId.String()); err != nil {
+			return err
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, newBid.Name, newBid.Description, newBid.Status, newBid.AuthorType, newBid.AuthorId, currentVersion+1, newBid.CreatedAt, newBid.Id)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Failed to rollback: %v", rollbackErr)})
-			return
+		newBid, err := s.getBidByIdAndVersion(ctx, bidId, version)
+		if err != nil {
+			return err
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertBidDiff(tx, ctx, newBid) {
-		return
-	}
+		updatedBid = newBid
+		updatedBid.Version = bid.Version + 1
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = s.saveTransition(ctx, bid.Status, updatedBid, ActorAuthor); err != nil {
+			return err
+		}
 
-	returningBid := Bid{
-		Id:          newBid.Id,
-		Name:        newBid.Name,
-		Description: newBid.Description,
-		Status:      newBid.Status,
-		AuthorType:  newBid.AuthorType,
-		AuthorId:    newBid.AuthorId,
-		Version:     currentVersion + 1,
-		CreatedAt:   newBid.CreatedAt,
+		return s.record(ctx, event.BidRolledBack, updatedBid, updatedBid)
+	})
+	if err != nil {
+		return Bid{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningBid)
+	return updatedBid, nil
 }
@@ -1,9 +1,31 @@
 package bid
 
-type Service struct{}
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+)
 
-func NewService() *Service {
-	return &Service{}
+type Service struct {
+	repo          BidRepository
+	tenders       tender.TenderRepository
+	tenderService *tender.Service
+	access        *access.Service
+	events        event.Outbox
+}
+
+func NewService(repo BidRepository, tenders tender.TenderRepository, tenderService *tender.Service, access *access.Service, events event.Outbox) *Service {
+	s := &Service{
+		repo:          repo,
+		tenders:       tenders,
+		tenderService: tenderService,
+		access:        access,
+		events:        events,
+	}
+
+	tenderService.OnStatus(tender.TenderStatusClosed, s.closeOpenBids)
+
+	return s
 }
 
 func (s *Service) List() {
@@ -0,0 +1,480 @@
+package bid_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/bid"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	router  *gin.Engine
+	store   *memory.Store
+	tenders *memory.TenderRepository
+	outbox  *memory.OutboxRepository
+	tender  tender.Tender
+	userIds map[string]string
+}
+
+func newFixture(t *testing.T, responsibles ...string) fixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	userIds := map[string]string{"bidder": store.AddEmployee("bidder")}
+	for _, username := range responsibles {
+		userIds[username] = store.AddEmployee(username)
+		store.AddResponsible(organizationId, userIds[username])
+	}
+
+	tenders := memory.NewTenderRepository(store)
+	created, err := tenders.Create(context.Background(), tender.Tender{
+		Name:            "Tender",
+		Status:          tender.TenderStatusPublished,
+		ServiceType:     tender.TenderServiceTypeDelivery,
+		Version:         1,
+		OrganizationId:  uuid.MustParse(organizationId),
+		CreatorUsername: responsibles[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessService := access.NewService(memory.NewAccessRepository(store))
+	outbox := memory.NewOutboxRepository(store)
+	service := bid.NewService(memory.NewBidRepository(store), tenders, tender.NewService(tenders, accessService, outbox), accessService, outbox)
+
+	cmd := commands.NewCommander(nil, service, nil, nil, nil, nil, nil, nil, page.NewCodec([]byte("test-secret")))
+
+	router := gin.New()
+	router.POST("/bids/new", cmd.AddBid)
+	router.PUT("/bids/:bidId/status", cmd.PutBidStatus)
+	router.PUT("/bids/:bidId/submit_decision", cmd.SubmitBidDecision)
+	router.PATCH("/bids/:bidId/edit", cmd.PatchBid)
+	router.GET("/bids/my", cmd.ListMy)
+	router.GET("/bids/:bidId", cmd.GetBid)
+	router.GET("/bids/:bidId/status", cmd.BidStatus)
+	router.PUT("/bids/:bidId/feedback", cmd.BidFeedback)
+	router.GET("/bids/:bidId/reviews", commands.RenameParam("bidId", "tenderId"), cmd.BidReviews)
+
+	return fixture{router: router, store: store, tenders: tenders, outbox: outbox, tender: created, userIds: userIds}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(method, target, &payload))
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func (f fixture) publishedBid(t *testing.T) bid.Bid {
+	t.Helper()
+
+	var created bid.Bid
+	code := f.do(t, http.MethodPost, "/bids/new?username=bidder", gin.H{
+		"name":        "Offer",
+		"description": "Best offer",
+		"tenderId":    f.tender.Id,
+		"authorType":  bid.BidAuthorUser,
+		"authorId":    f.userIds["bidder"],
+	}, &created)
+	if code != http.StatusCreated {
+		t.Fatalf("add code = %d, want %d", code, http.StatusCreated)
+	}
+
+	var published bid.Bid
+	code = f.do(t, http.MethodPut, "/bids/"+created.Id.String()+"/status?status=Published&username=bidder", nil, &published)
+	if code != http.StatusOK {
+		t.Fatalf("publish code = %d, want %d", code, http.StatusOK)
+	}
+
+	return published
+}
+
+func (f fixture) decide(t *testing.T, b bid.Bid, username string, decision bid.BidDecision) (bid.Bid, int) {
+	t.Helper()
+
+	var decided bid.Bid
+	code := f.do(t, http.MethodPut, "/bids/"+b.Id.String()+"/submit_decision?decision="+string(decision)+"&username="+username, nil, &decided)
+
+	return decided, code
+}
+
+func TestApprovalNeedsQuorum(t *testing.T) {
+	f := newFixture(t, "first", "second", "third", "fourth")
+	published := f.publishedBid(t)
+
+	for _, username := range []string{"first", "second"} {
+		decided, code := f.decide(t, published, username, bid.BidDecisionApproved)
+		if code != http.StatusOK || decided.Status != bid.BidStatusPublished {
+			t.Fatalf("%s approval: code = %d, status = %s", username, code, decided.Status)
+		}
+	}
+
+	decided, code := f.decide(t, published, "third", bid.BidDecisionApproved)
+	if code != http.StatusOK || decided.Status != bid.BidStatusApproved {
+		t.Fatalf("third approval: code = %d, status = %s", code, decided.Status)
+	}
+
+	closed, err := f.tenders.Get(context.Background(), f.tender.Id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed.Status != tender.TenderStatusClosed {
+		t.Fatalf("tender status = %s, want %s", closed.Status, tender.TenderStatusClosed)
+	}
+}
+
+func TestOnlyDecidingRolesVote(t *testing.T) {
+	f := newFixture(t, "first")
+	organizationId := f.tender.OrganizationId.String()
+	f.store.AddMember(organizationId, f.store.AddEmployee("evaluator"), access.RoleEvaluator)
+	f.store.AddMember(organizationId, f.store.AddEmployee("viewer"), access.RoleViewer)
+	published := f.publishedBid(t)
+
+	if _, code := f.decide(t, published, "viewer", bid.BidDecisionApproved); code != http.StatusForbidden {
+		t.Fatalf("viewer decision code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	decided, code := f.decide(t, published, "evaluator", bid.BidDecisionApproved)
+	if code != http.StatusOK || decided.Status != bid.BidStatusPublished {
+		t.Fatalf("evaluator approval: code = %d, status = %s", code, decided.Status)
+	}
+
+	decided, code = f.decide(t, published, "first", bid.BidDecisionApproved)
+	if code != http.StatusOK || decided.Status != bid.BidStatusApproved {
+		t.Fatalf("owner approval: code = %d, status = %s", code, decided.Status)
+	}
+}
+
+func TestGetVisibleToAuthorAndResponsibles(t *testing.T) {
+	f := newFixture(t, "owner")
+	f.store.AddEmployee("stranger")
+	published := f.publishedBid(t)
+	target := "/bids/" + published.Id.String()
+
+	for _, username := range []string{"bidder", "owner"} {
+		var found bid.Bid
+		if code := f.do(t, http.MethodGet, target+"?username="+username, nil, &found); code != http.StatusOK {
+			t.Fatalf("%s get code = %d, want %d", username, code, http.StatusOK)
+		}
+		if found.Id != published.Id || found.Status != bid.BidStatusPublished {
+			t.Fatalf("%s found bid = %+v", username, found)
+		}
+	}
+
+	if code := f.do(t, http.MethodGet, target+"?username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger get code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodGet, target, nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("anonymous get code = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestUserBidNeedsItsAuthor(t *testing.T) {
+	f := newFixture(t, "owner")
+	f.userIds["other"] = f.store.AddEmployee("other")
+
+	body := gin.H{
+		"name":        "Offer",
+		"description": "Best offer",
+		"tenderId":    f.tender.Id,
+		"authorType":  bid.BidAuthorUser,
+		"authorId":    f.userIds["bidder"],
+	}
+
+	if code := f.do(t, http.MethodPost, "/bids/new", body, nil); code != http.StatusUnauthorized {
+		t.Fatalf("anonymous add code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.do(t, http.MethodPost, "/bids/new?username=other", body, nil); code != http.StatusForbidden {
+		t.Fatalf("other user add code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodPost, "/bids/new?username=bidder", body, nil); code != http.StatusCreated {
+		t.Fatalf("author add code = %d, want %d", code, http.StatusCreated)
+	}
+}
+
+func TestOrganizationBidNeedsSubmitPermission(t *testing.T) {
+	f := newFixture(t, "owner")
+	vendorId := f.store.AddOrganization("vendor")
+	f.store.AddResponsible(vendorId, f.store.AddEmployee("vendor"))
+
+	body := gin.H{
+		"name":        "Offer",
+		"description": "Best offer",
+		"tenderId":    f.tender.Id,
+		"authorType":  bid.BidAuthorOrganization,
+		"authorId":    vendorId,
+	}
+
+	if code := f.do(t, http.MethodPost, "/bids/new?username=bidder", body, nil); code != http.StatusForbidden {
+		t.Fatalf("non-member add code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodPost, "/bids/new?username=vendor", body, nil); code != http.StatusCreated {
+		t.Fatalf("member add code = %d, want %d", code, http.StatusCreated)
+	}
+}
+
+func TestMyBidsFollowSubmitPermission(t *testing.T) {
+	f := newFixture(t, "owner")
+	vendorId := f.store.AddOrganization("vendor")
+	f.store.AddResponsible(vendorId, f.store.AddEmployee("vendor"))
+	f.store.AddMember(vendorId, f.store.AddEmployee("viewer"), access.RoleViewer)
+
+	_, err := memory.NewBidRepository(f.store).Create(context.Background(), bid.Bid{
+		Name:       "Offer",
+		Status:     bid.BidStatusCreated,
+		TenderId:   f.tender.Id,
+		AuthorType: bid.BidAuthorOrganization,
+		AuthorId:   vendorId,
+		Version:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for username, want := range map[string]int{"vendor": 1, "viewer": 0} {
+		var bids []bid.Bid
+		if code := f.do(t, http.MethodGet, "/bids/my?username="+username, nil, &bids); code != http.StatusOK {
+			t.Fatalf("%s list code = %d, want %d", username, code, http.StatusOK)
+		}
+		if len(bids) != want {
+			t.Fatalf("%s sees %d bids, want %d", username, len(bids), want)
+		}
+	}
+}
+
+func TestDeadlineBlocksOnlyPlacingBids(t *testing.T) {
+	f := newFixture(t, "owner")
+	published := f.publishedBid(t)
+
+	deadline := time.Now().Add(-time.Hour)
+	f.tender.BidDeadline = &deadline
+	if err := f.tenders.Update(context.Background(), f.tender); err != nil {
+		t.Fatal(err)
+	}
+
+	target := "/bids/" + published.Id.String()
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=bidder", gin.H{"name": "Late"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("edit after deadline code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := f.do(t, http.MethodPut, target+"/status?status=Cancelled&username=bidder", nil, nil); code != http.StatusOK {
+		t.Fatalf("cancel after deadline code = %d, want %d", code, http.StatusOK)
+	}
+
+	draft, err := f.tenders.Create(context.Background(), tender.Tender{
+		Name:            "Draft",
+		Status:          tender.TenderStatusCreated,
+		ServiceType:     tender.TenderServiceTypeDelivery,
+		Version:         1,
+		OrganizationId:  f.tender.OrganizationId,
+		CreatorUsername: "owner",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(username string) int {
+		return f.do(t, http.MethodPost, "/bids/new?username="+username, gin.H{
+			"name":        "Offer",
+			"description": "Best offer",
+			"tenderId":    draft.Id,
+			"authorType":  bid.BidAuthorUser,
+			"authorId":    f.userIds[username],
+		}, nil)
+	}
+	if code := add("bidder"); code != http.StatusNotFound {
+		t.Fatalf("bid on hidden tender code = %d, want %d", code, http.StatusNotFound)
+	}
+	if code := add("owner"); code != http.StatusConflict {
+		t.Fatalf("bid on own draft tender code = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestReviewsOfOrganizationBids(t *testing.T) {
+	f := newFixture(t, "owner")
+	vendorId := f.store.AddOrganization("vendor")
+	f.store.AddResponsible(vendorId, f.store.AddEmployee("vendor"))
+
+	created, err := memory.NewBidRepository(f.store).Create(context.Background(), bid.Bid{
+		Name:       "Offer",
+		Status:     bid.BidStatusPublished,
+		TenderId:   f.tender.Id,
+		AuthorType: bid.BidAuthorOrganization,
+		AuthorId:   vendorId,
+		Version:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code := f.do(t, http.MethodPut, "/bids/"+created.Id.String()+"/feedback?bidFeedback=Too+expensive&username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("feedback code = %d, want %d", code, http.StatusOK)
+	}
+
+	var reviews []bid.BidReview
+	target := "/bids/" + f.tender.Id.String() + "/reviews?authorUsername=vendor&requesterUsername=owner"
+	if code := f.do(t, http.MethodGet, target, nil, &reviews); code != http.StatusOK {
+		t.Fatalf("reviews code = %d, want %d", code, http.StatusOK)
+	}
+	if len(reviews) != 1 || reviews[0].Description != "Too expensive" {
+		t.Fatalf("reviews = %+v, want the feedback on the organization bid", reviews)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target+"&offset=1", nil))
+	if recorder.Code != http.StatusOK || strings.TrimSpace(recorder.Body.String()) != "[]" {
+		t.Fatalf("reviews past the end: code = %d, body = %s, want an empty list", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestRejectionRejectsBid(t *testing.T) {
+	f := newFixture(t, "first", "second")
+	published := f.publishedBid(t)
+
+	if _, code := f.decide(t, published, "bidder", bid.BidDecisionApproved); code != http.StatusForbidden {
+		t.Fatalf("bidder decision code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	f.decide(t, published, "first", bid.BidDecisionApproved)
+
+	decided, code := f.decide(t, published, "second", bid.BidDecisionRejected)
+	if code != http.StatusOK || decided.Status != bid.BidStatusRejected {
+		t.Fatalf("rejection: code = %d, status = %s", code, decided.Status)
+	}
+
+	if code := f.do(t, http.MethodPatch, "/bids/"+published.Id.String()+"/edit?username=bidder", gin.H{"name": "Cheaper"}, nil); code != http.StatusConflict {
+		t.Fatalf("edit rejected code = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestCancelledBidCannotBeRepublished(t *testing.T) {
+	f := newFixture(t, "first")
+	published := f.publishedBid(t)
+	target := "/bids/" + published.Id.String() + "/status?username=bidder&status="
+
+	if code := f.do(t, http.MethodPut, target+string(bid.BidStatusCancelled), nil, nil); code != http.StatusOK {
+		t.Fatalf("cancel code = %d, want %d", code, http.StatusOK)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, target+string(bid.BidStatusPublished), nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("republish code = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+
+	var body struct {
+		AllowedStatuses []bid.BidStatus `json:"allowedStatuses"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.AllowedStatuses == nil || len(body.AllowedStatuses) != 0 {
+		t.Fatalf("allowed statuses = %v, want empty list", body.AllowedStatuses)
+	}
+
+	if code := f.do(t, http.MethodPatch, "/bids/"+published.Id.String()+"/edit?username=bidder", gin.H{"name": "Revived"}, nil); code != http.StatusConflict {
+		t.Fatalf("edit cancelled code = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestClosingTenderSettlesOpenBids(t *testing.T) {
+	f := newFixture(t, "first")
+	winner := f.publishedBid(t)
+	loser := f.publishedBid(t)
+
+	if decided, code := f.decide(t, winner, "first", bid.BidDecisionApproved); code != http.StatusOK || decided.Status != bid.BidStatusApproved {
+		t.Fatalf("approval: code = %d, status = %s", code, decided.Status)
+	}
+
+	var status bid.BidStatus
+	if code := f.do(t, http.MethodGet, "/bids/"+loser.Id.String()+"/status?username=bidder", nil, &status); code != http.StatusOK {
+		t.Fatalf("status code = %d, want %d", code, http.StatusOK)
+	}
+	if status != bid.BidStatusNotSelected {
+		t.Fatalf("loser status = %s, want %s", status, bid.BidStatusNotSelected)
+	}
+
+	if code := f.do(t, http.MethodPatch, "/bids/"+loser.Id.String()+"/edit?username=bidder", gin.H{"name": "Late"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("patch after close code = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	code := f.do(t, http.MethodPost, "/bids/new?username=bidder", gin.H{
+		"name":        "Late offer",
+		"description": "Too late",
+		"tenderId":    f.tender.Id,
+		"authorType":  bid.BidAuthorUser,
+		"authorId":    f.userIds["bidder"],
+	}, nil)
+	if code != http.StatusBadRequest {
+		t.Fatalf("add after close code = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestApprovalRelaysEvents(t *testing.T) {
+	f := newFixture(t, "first")
+	published := f.publishedBid(t)
+	f.decide(t, published, "first", bid.BidDecisionApproved)
+
+	var types []event.Type
+	bus := event.NewBus()
+	bus.Subscribe(func(ctx context.Context, e event.Event) error {
+		if e.OrganizationId != f.tender.OrganizationId || e.TenderId != f.tender.Id {
+			t.Errorf("event %s has organization %s, tender %s", e.Type, e.OrganizationId, e.TenderId)
+		}
+		types = append(types, e.Type)
+		return nil
+	})
+
+	failing := event.NewBus()
+	failing.Subscribe(func(ctx context.Context, e event.Event) error {
+		return errors.New("recorder failed")
+	})
+	if _, err := event.NewRelay(f.outbox, failing, bus).Deliver(context.Background()); err == nil || len(types) != 0 {
+		t.Fatalf("deliver with failing recorder = %v, published %v", err, types)
+	}
+
+	relay := event.NewRelay(f.outbox, event.NewBus(), bus)
+	if delivered, err := relay.Deliver(context.Background()); err != nil || delivered != 5 {
+		t.Fatalf("deliver = %d, %v", delivered, err)
+	}
+	if delivered, err := relay.Deliver(context.Background()); err != nil || delivered != 0 {
+		t.Fatalf("redeliver = %d, %v", delivered, err)
+	}
+
+	want := []event.Type{event.BidCreated, event.BidPublished, event.BidDecisionMade, event.BidApproved, event.TenderClosed}
+	if !slices.Equal(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+}
@@ -0,0 +1,114 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"slices"
+)
+
+type Actor string
+
+const (
+	ActorAuthor      Actor = "Author"
+	ActorResponsible Actor = "Responsible"
+	ActorSystem      Actor = "System"
+)
+
+type transition struct {
+	from   BidStatus
+	to     BidStatus
+	actors []Actor
+	effect func(s *Service, ctx context.Context, bid Bid) error
+}
+
+var transitions = []transition{
+	{from: BidStatusCreated, to: BidStatusPublished, actors: []Actor{ActorAuthor}},
+	{from: BidStatusCreated, to: BidStatusCancelled, actors: []Actor{ActorAuthor, ActorSystem}},
+	{from: BidStatusPublished, to: BidStatusCancelled, actors: []Actor{ActorAuthor}},
+	{from: BidStatusPublished, to: BidStatusApproved, actors: []Actor{ActorResponsible}, effect: (*Service).closeTender},
+	{from: BidStatusPublished, to: BidStatusRejected, actors: []Actor{ActorResponsible}},
+	{from: BidStatusPublished, to: BidStatusNotSelected, actors: []Actor{ActorSystem}},
+}
+
+var statusEvents = map[BidStatus]event.Type{
+	BidStatusPublished:   event.BidPublished,
+	BidStatusCancelled:   event.BidCancelled,
+	BidStatusApproved:    event.BidApproved,
+	BidStatusRejected:    event.BidRejected,
+	BidStatusNotSelected: event.BidNotSelected,
+}
+
+// AllowedTransitions lists the statuses actor may move a bid in status from to.
+func AllowedTransitions(from BidStatus, actor Actor) []BidStatus {
+	allowed := []BidStatus{}
+	for _, t := range transitions {
+		if t.from == from && slices.Contains(t.actors, actor) {
+			allowed = append(allowed, t.to)
+		}
+	}
+
+	return allowed
+}
+
+func findTransition(from BidStatus, to BidStatus, actor Actor) (transition, error) {
+	for _, t := range transitions {
+		if t.from == from && t.to == to && slices.Contains(t.actors, actor) {
+			return t, nil
+		}
+	}
+
+	return transition{}, errs.InvalidTransition(fmt.Sprintf("Bid can't be moved from %v to %v", from, to), AllowedTransitions(from, actor))
+}
+
+// terminal reports whether no transition leaves status.
+func terminal(status BidStatus) bool {
+	return !slices.ContainsFunc(transitions, func(t transition) bool {
+		return t.from == status
+	})
+}
+
+// saveTransition stores bid as its new version, enforcing the state machine
+// and running the transition's effect when its status differs from the
+// previous one. Bids in a terminal status can't be changed at all.
+func (s *Service) saveTransition(ctx context.Context, from BidStatus, bid Bid, actor Actor) error {
+	if bid.Status == from {
+		if terminal(from) {
+			return errs.Conflict(fmt.Sprintf("Bid is %v and can't be changed", from))
+		}
+
+		return s.saveBid(ctx, bid)
+	}
+
+	t, err := findTransition(from, bid.Status, actor)
+	if err != nil {
+		return err
+	}
+
+	if err = s.saveBid(ctx, bid); err != nil {
+		return err
+	}
+
+	if err = s.record(ctx, statusEvents[bid.Status], bid, bid); err != nil {
+		return err
+	}
+
+	if t.effect != nil {
+		return t.effect(s, ctx, bid)
+	}
+
+	return nil
+}
+
+func (s *Service) transition(ctx context.Context, bid Bid, status BidStatus, actor Actor) (Bid, error) {
+	updatedBid := bid
+	updatedBid.Status = status
+	updatedBid.Version++
+
+	if err := s.saveTransition(ctx, bid.Status, updatedBid, actor); err != nil {
+		return Bid{}, err
+	}
+
+	return updatedBid, nil
+}
@@ -0,0 +1,94 @@
+package bid
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+)
+
+func (s *Service) SubmitDecision(ctx context.Context, bidId string, username string, decision BidDecision) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
+	}
+
+	if err := validateDecision(decision); err != nil {
+		return Bid{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
+	}
+
+	userId, err := s.getUserId(ctx, username)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	var decided Bid
+	err = s.inTx(ctx, func(ctx context.Context) error {
+		bid, err := s.getBidById(ctx, bidId)
+		if err != nil {
+			return err
+		}
+
+		if err = s.checkTenderPermission(ctx, username, access.PermissionBidDecide, bid.TenderId.String()); err != nil {
+			return err
+		}
+
+		status := BidStatusApproved
+		if decision == BidDecisionRejected {
+			status = BidStatusRejected
+		}
+
+		if _, err = findTransition(bid.Status, status, ActorResponsible); err != nil {
+			return err
+		}
+
+		t, err := s.getTender(ctx, bid.TenderId.String())
+		if err != nil {
+			return err
+		}
+
+		if t.Status == tender.TenderStatusClosed {
+			return errs.Validation("Tender is already closed")
+		}
+
+		if err = s.repo.SaveDecision(ctx, bidId, userId, decision); err != nil {
+			return err
+		}
+
+		if err = s.record(ctx, event.BidDecisionMade, bid, DecisionMade{Bid: bid, Decision: decision, Username: username}); err != nil {
+			return err
+		}
+
+		if decision == BidDecisionRejected {
+			decided, err = s.transition(ctx, bid, BidStatusRejected, ActorResponsible)
+			return err
+		}
+
+		approvals, err := s.repo.CountDecisions(ctx, bidId, BidDecisionApproved)
+		if err != nil {
+			return err
+		}
+
+		quorum, err := s.access.Quorum(ctx, t.OrganizationId.String())
+		if err != nil {
+			return err
+		}
+
+		if approvals < quorum {
+			decided = bid
+			return nil
+		}
+
+		decided, err = s.transition(ctx, bid, BidStatusApproved, ActorResponsible)
+		return err
+	})
+	if err != nil {
+		return Bid{}, err
+	}
+
+	return decided, nil
+}
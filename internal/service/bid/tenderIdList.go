@@ -1,60 +1,59 @@
 package bid
 
 import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
 )
 
-func (s *Service) TenderIdList(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tenderId, ok := getTenderId(ctx)
-	if !ok {
-		return
+func (s *Service) TenderIdList(ctx context.Context, tenderId string, username string, request page.Request) (page.Page[Bid], error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	limit, ok := getLimit(ctx)
-	if !ok {
-		return
+	if err := validatePage(request); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	offset, ok := getOffset(ctx)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
+	if err := s.checkTenderExistence(ctx, tenderId); err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
+	authors, err := s.access.Authorship(ctx, username)
+	if err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	if tenderExists := checkTenderExistence(db, ctx, tenderId); !tenderExists {
-		return
+	responsible, err := s.canOnTender(ctx, username, access.PermissionBidView, tenderId)
+	if err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	authorId, ok := getAuthorId(db, ctx, username)
-	if !ok {
-		return
-	}
+	if !responsible {
+		hasOwnBids, err := s.repo.HasAuthoredBids(ctx, tenderId, authors)
+		if err != nil {
+			return page.Page[Bid]{}, err
+		}
 
-	query := "SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at FROM bid WHERE tender_id = $1 AND author_id = $2 ORDER BY name LIMIT $3 OFFSET $4"
+		if !hasOwnBids {
+			return page.Page[Bid]{}, errs.Forbidden("User is neither responsible for the tender organization nor a bid author")
+		}
+	}
 
-	rows, err := db.Query(query, tenderId, authorId, limit, offset)
+	bids, err := s.repo.ListByTender(ctx, tenderId, authors, responsible, request.Lookahead())
 	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+		return page.Page[Bid]{}, err
 	}
-	defer rows.Close()
 
-	bids, ok := extractBids(ctx, rows)
-	if !ok {
-		return
+	total, err := s.repo.CountByTender(ctx, tenderId, authors, responsible)
+	if err != nil {
+		return page.Page[Bid]{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, bids)
+	return page.New(bids, request.Limit, total, pageKey), nil
 }
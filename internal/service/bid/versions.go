@@ -0,0 +1,60 @@
+package bid
+
+import "context"
+
+// Versions also returns the current version of the bid, which changes
+// whenever the history does.
+func (s *Service) Versions(ctx context.Context, bidId string, username string, limit int, offset int) ([]Bid, int, error) {
+	if err := validateBidId(bidId); err != nil {
+		return nil, 0, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return nil, 0, err
+	}
+
+	bid, err := s.getBidById(ctx, bidId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = s.checkVisible(ctx, username, bid); err != nil {
+		return nil, 0, err
+	}
+
+	versions, err := s.repo.ListVersions(ctx, bidId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return versions, bid.Version, nil
+}
+
+func (s *Service) Version(ctx context.Context, bidId string, username string, version int) (Bid, error) {
+	if err := validateBidId(bidId); err != nil {
+		return Bid{}, err
+	}
+
+	if err := validateVersion(version); err != nil {
+		return Bid{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Bid{}, err
+	}
+
+	bid, err := s.getBidById(ctx, bidId)
+	if err != nil {
+		return Bid{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, bid); err != nil {
+		return Bid{}, err
+	}
+
+	return s.getBidByIdAndVersion(ctx, bidId, version)
+}
@@ -0,0 +1,66 @@
+package errs
+
+import "errors"
+
+var (
+	ErrValidation    = errors.New("validation failed")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrPrecondition  = errors.New("precondition failed")
+	ErrUnprocessable = errors.New("unprocessable")
+)
+
+// Error carries a human-readable reason while matching one of the sentinel
+// errors above via errors.Is.
+type Error struct {
+	kind    error
+	reason  string
+	details map[string]any
+}
+
+func (e *Error) Error() string {
+	return e.reason
+}
+
+// Details returns extra fields to report alongside the reason.
+func (e *Error) Details() map[string]any {
+	return e.details
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+func Validation(reason string) error {
+	return &Error{kind: ErrValidation, reason: reason}
+}
+
+func Unauthorized(reason string) error {
+	return &Error{kind: ErrUnauthorized, reason: reason}
+}
+
+func Forbidden(reason string) error {
+	return &Error{kind: ErrForbidden, reason: reason}
+}
+
+func NotFound(reason string) error {
+	return &Error{kind: ErrNotFound, reason: reason}
+}
+
+func Conflict(reason string) error {
+	return &Error{kind: ErrConflict, reason: reason}
+}
+
+func PreconditionFailed(reason string) error {
+	return &Error{kind: ErrPrecondition, reason: reason}
+}
+
+func Unprocessable(reason string) error {
+	return &Error{kind: ErrUnprocessable, reason: reason}
+}
+
+func InvalidTransition(reason string, allowed any) error {
+	return &Error{kind: ErrValidation, reason: reason, details: map[string]any{"allowedStatuses": allowed}}
+}
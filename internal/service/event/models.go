@@ -0,0 +1,109 @@
+package event
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Type string
+
+const (
+	TenderCreated    Type = "TenderCreated"
+	TenderUpdated    Type = "TenderUpdated"
+	TenderPublished  Type = "TenderPublished"
+	TenderClosed     Type = "TenderClosed"
+	TenderRolledBack Type = "TenderRolledBack"
+
+	BidCreated           Type = "BidCreated"
+	BidUpdated           Type = "BidUpdated"
+	BidPublished         Type = "BidPublished"
+	BidCancelled         Type = "BidCancelled"
+	BidApproved          Type = "BidApproved"
+	BidRejected          Type = "BidRejected"
+	BidNotSelected       Type = "BidNotSelected"
+	BidRolledBack        Type = "BidRolledBack"
+	BidDecisionMade      Type = "BidDecisionMade"
+	BidFeedbackSubmitted Type = "BidFeedbackSubmitted"
+)
+
+// ownerVisible lists the events the tender owner may see. Events about bids
+// that are not published yet are left out, since the owner can't see those bids.
+var ownerVisible = map[Type]bool{
+	TenderCreated:        true,
+	TenderUpdated:        true,
+	TenderPublished:      true,
+	TenderClosed:         true,
+	TenderRolledBack:     true,
+	BidPublished:         true,
+	BidApproved:          true,
+	BidRejected:          true,
+	BidNotSelected:       true,
+	BidDecisionMade:      true,
+	BidFeedbackSubmitted: true,
+}
+
+// authorVisible lists the events the author of a bid may see about it.
+var authorVisible = map[Type]bool{
+	BidCreated:           true,
+	BidUpdated:           true,
+	BidPublished:         true,
+	BidCancelled:         true,
+	BidApproved:          true,
+	BidRejected:          true,
+	BidNotSelected:       true,
+	BidRolledBack:        true,
+	BidDecisionMade:      true,
+	BidFeedbackSubmitted: true,
+}
+
+// OwnerVisible reports whether responsibles of the organization that owns the
+// tender may be notified about events of type t.
+func OwnerVisible(t Type) bool {
+	return ownerVisible[t]
+}
+
+// AuthorVisible reports whether the author of a bid may be notified about
+// events of type t about it.
+func AuthorVisible(t Type) bool {
+	return authorVisible[t]
+}
+
+// Known reports whether t is an event type.
+func Known(t Type) bool {
+	return ownerVisible[t] || authorVisible[t]
+}
+
+// Event is a change to a tender or one of its bids. OrganizationId is always
+// the organization that owns the tender. Sequence is assigned when the event is
+// published and orders events by the commit of their publication.
+//
+// Public events are changes to published tenders that anyone may see. Events
+// about a bid name its author, who may see them too.
+type Event struct {
+	Id             int64           `json:"id"`
+	Sequence       int64           `json:"sequence,omitempty"`
+	Type           Type            `json:"type"`
+	OrganizationId uuid.UUID       `json:"organizationId"`
+	TenderId       uuid.UUID       `json:"tenderId"`
+	BidId          *uuid.UUID      `json:"bidId,omitempty"`
+	AuthorType     string          `json:"authorType,omitempty"`
+	AuthorId       string          `json:"authorId,omitempty"`
+	Public         bool            `json:"public"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+func New(eventType Type, organizationId uuid.UUID, tenderId uuid.UUID, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:           eventType,
+		OrganizationId: organizationId,
+		TenderId:       tenderId,
+		Payload:        data,
+	}, nil
+}
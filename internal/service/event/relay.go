@@ -0,0 +1,112 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"log"
+	"time"
+)
+
+const (
+	relayBatchSize = 100
+	relayLock      = "outbox-relay"
+)
+
+// Relay moves events from the outbox to its consumers in two steps. The
+// recorder runs inside the transaction that marks events published, so what it
+// writes through ctx, such as webhook deliveries, is committed exactly once
+// with the marks. The publisher runs after the commit and sees each event at
+// most once: a crash in between skips it, and live consumers such as event
+// streams catch up from the Log.
+//
+// Pending events are taken in id order, which doesn't follow the order they
+// commit in: an event can commit after a higher id was relayed. Relays take
+// turns, and each batch is numbered after the ones before it, so sequences do
+// follow the order of publication and readers of the Log resume from them.
+type Relay struct {
+	repo      Repository
+	recorder  Publisher
+	publisher Publisher
+}
+
+func NewRelay(repo Repository, recorder Publisher, publisher Publisher) *Relay {
+	return &Relay{
+		repo:      repo,
+		recorder:  recorder,
+		publisher: publisher,
+	}
+}
+
+// Deliver relays one batch of pending events and reports how many were marked
+// published. A recorder error leaves the whole batch pending, and nothing is
+// relayed while another relay holds its turn.
+func (r *Relay) Deliver(ctx context.Context) (int, error) {
+	var events []Event
+
+	err := repository.InTx(ctx, r.repo, func(ctx context.Context) error {
+		events = nil
+
+		locked, err := r.repo.TryLock(ctx, relayLock)
+		if err != nil || !locked {
+			return err
+		}
+
+		events, err = r.repo.ListPending(ctx, relayBatchSize)
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			if err = r.recorder.Publish(ctx, event); err != nil {
+				return err
+			}
+			ids = append(ids, event.Id)
+		}
+
+		sequences, err := r.repo.MarkPublished(ctx, ids, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			events[i].Sequence = sequences[i]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var publishErr error
+	for _, event := range events {
+		publishErr = errors.Join(publishErr, r.publisher.Publish(ctx, event))
+	}
+
+	return len(events), publishErr
+}
+
+// Run delivers pending events every interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				delivered, err := r.Deliver(ctx)
+				if err != nil {
+					log.Println("Error relaying events:", err)
+				}
+				if err != nil || delivered < relayBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
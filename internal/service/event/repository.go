@@ -0,0 +1,32 @@
+package event
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"time"
+)
+
+// Outbox records events. Append must be called inside the transaction that
+// made the change, so the event is stored if and only if the change is.
+type Outbox interface {
+	Append(ctx context.Context, event Event) error
+}
+
+type Repository interface {
+	repository.Transactor
+	repository.Locker
+	Outbox
+	// ListPending returns undelivered events in order. Inside a transaction the
+	// rows stay locked against other relays until it ends.
+	ListPending(ctx context.Context, limit int) ([]Event, error)
+	// MarkPublished numbers the events after every event published before, in
+	// the order of ids, and returns their sequences in that order.
+	MarkPublished(ctx context.Context, ids []int64, at time.Time) ([]int64, error)
+}
+
+// Log reads delivered events back, e.g. to resume an event stream.
+type Log interface {
+	// ListAfter returns published events with sequences greater than
+	// afterSequence in order.
+	ListAfter(ctx context.Context, afterSequence int64, limit int) ([]Event, error)
+}
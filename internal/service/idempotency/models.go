@@ -0,0 +1,19 @@
+package idempotency
+
+import "time"
+
+type Response struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       []byte
+}
+
+// Record is a reserved idempotency key. Response stays nil until the request
+// that reserved the key has finished.
+type Record struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	Response    *Response
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,14 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Reserve(ctx context.Context, record Record, now time.Time) (bool, error)
+	Get(ctx context.Context, scope string, key string) (Record, error)
+	Complete(ctx context.Context, scope string, key string, response Response) error
+	Delete(ctx context.Context, scope string, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"log"
+	"time"
+)
+
+type Service struct {
+	repo Repository
+	ttl  time.Duration
+}
+
+func NewService(repo Repository, ttl time.Duration) *Service {
+	return &Service{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Begin reserves key for a request with the given fingerprint. It returns the
+// stored response when the same request has already been completed and nil
+// when the caller should handle the request and then Complete or Release it.
+func (s *Service) Begin(ctx context.Context, scope string, key string, fingerprint string) (*Response, error) {
+	if key == "" || len(key) > 255 {
+		return nil, errs.Validation("Invalid Idempotency-Key header")
+	}
+
+	now := time.Now().UTC()
+
+	reserved, err := s.repo.Reserve(ctx, Record{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(s.ttl),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	record, err := s.repo.Get(ctx, scope, key)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, errs.Conflict("Idempotency-Key was released concurrently, retry the request")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, errs.Unprocessable("Idempotency-Key was already used with a different request")
+	}
+
+	if record.Response == nil {
+		return nil, errs.Conflict("A request with this Idempotency-Key is still being processed")
+	}
+
+	return record.Response, nil
+}
+
+func (s *Service) Complete(ctx context.Context, scope string, key string, response Response) error {
+	return s.repo.Complete(ctx, scope, key, response)
+}
+
+// Release frees a reserved key so that a failed request can be retried.
+func (s *Service) Release(ctx context.Context, scope string, key string) error {
+	return s.repo.Delete(ctx, scope, key)
+}
+
+// Cleanup deletes expired keys every interval until ctx is done.
+func (s *Service) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.repo.DeleteExpired(ctx, time.Now().UTC()); err != nil {
+				log.Println("Error deleting expired idempotency keys:", err)
+			}
+		}
+	}
+}
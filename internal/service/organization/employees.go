@@ -0,0 +1,189 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+)
+
+func (s *Service) Register(ctx context.Context, registration Registration) (Employee, error) {
+	if err := validateUsername(registration.Username); err != nil {
+		return Employee{}, err
+	}
+
+	if err := validatePersonName(registration.FirstName); err != nil {
+		return Employee{}, err
+	}
+
+	if err := validatePersonName(registration.LastName); err != nil {
+		return Employee{}, err
+	}
+
+	employee := Employee{
+		Username:  registration.Username,
+		FirstName: registration.FirstName,
+		LastName:  registration.LastName,
+	}
+
+	if registration.Password != "" {
+		hash, err := s.passwords.HashPassword(registration.Password)
+		if err != nil {
+			return Employee{}, err
+		}
+		employee.PasswordHash = hash
+	}
+
+	var created Employee
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		taken, err := s.access.UserExists(ctx, registration.Username)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errs.Conflict("Username is already taken")
+		}
+
+		created, err = s.repo.CreateEmployee(ctx, employee)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return errs.Conflict("Username is already taken")
+		}
+
+		return err
+	})
+	if err != nil {
+		return Employee{}, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) ListEmployees(ctx context.Context, username string, limit int, offset int) ([]Employee, error) {
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return nil, err
+	}
+
+	employees, err := s.repo.ListEmployees(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Employee{}, employees...), nil
+}
+
+func (s *Service) GetEmployee(ctx context.Context, employeeId string, username string) (Employee, error) {
+	if err := validateId(employeeId, "employeeId"); err != nil {
+		return Employee{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Employee{}, err
+	}
+
+	return s.getEmployee(ctx, employeeId)
+}
+
+// UpdateEmployee changes the names of an employee. Employees can only edit
+// themselves.
+func (s *Service) UpdateEmployee(ctx context.Context, employeeId string, username string, patch EmployeePatch) (Employee, error) {
+	if err := validateId(employeeId, "employeeId"); err != nil {
+		return Employee{}, err
+	}
+
+	if patch.FirstName != nil {
+		if err := validatePersonName(*patch.FirstName); err != nil {
+			return Employee{}, err
+		}
+	}
+
+	if patch.LastName != nil {
+		if err := validatePersonName(*patch.LastName); err != nil {
+			return Employee{}, err
+		}
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Employee{}, err
+	}
+
+	var updated Employee
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		employee, err := s.getEmployee(ctx, employeeId)
+		if err != nil {
+			return err
+		}
+
+		if employee.Username != username {
+			return errs.Forbidden("Employees can only edit themselves")
+		}
+
+		if patch.FirstName != nil {
+			employee.FirstName = *patch.FirstName
+		}
+
+		if patch.LastName != nil {
+			employee.LastName = *patch.LastName
+		}
+
+		updated, err = s.repo.UpdateEmployee(ctx, employee)
+		return err
+	})
+	if err != nil {
+		return Employee{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteEmployee removes an employee together with its memberships. Employees
+// that created tenders or are the last owner of an organization stay.
+func (s *Service) DeleteEmployee(ctx context.Context, employeeId string, username string) (Employee, error) {
+	if err := validateId(employeeId, "employeeId"); err != nil {
+		return Employee{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Employee{}, err
+	}
+
+	var deleted Employee
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		employee, err := s.getEmployee(ctx, employeeId)
+		if err != nil {
+			return err
+		}
+
+		if employee.Username != username {
+			return errs.Forbidden("Employees can only delete themselves")
+		}
+
+		responsible, err := s.repo.GetResponsible(ctx, employeeId)
+		if err == nil {
+			err = s.checkNotLastOwner(ctx, responsible)
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		err = s.repo.DeleteEmployee(ctx, employeeId)
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return errs.Conflict("Employee has created tenders and can't be deleted")
+		}
+		if err != nil {
+			return err
+		}
+
+		deleted = employee
+
+		return nil
+	})
+	if err != nil {
+		return Employee{}, err
+	}
+
+	return deleted, nil
+}
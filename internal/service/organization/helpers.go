@@ -0,0 +1,175 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/google/uuid"
+	"strings"
+	"unicode"
+)
+
+func validateId(id string, name string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errs.Validation("Invalid " + name)
+	}
+
+	return nil
+}
+
+func validateUsername(username string) error {
+	if username == "" || len(username) > 50 || strings.IndexFunc(username, unicode.IsSpace) >= 0 {
+		return errs.Validation("Username must be 1 to 50 characters long without spaces")
+	}
+
+	return nil
+}
+
+func validatePersonName(name string) error {
+	if len(name) > 50 {
+		return errs.Validation("First and last names must be at most 50 characters long")
+	}
+
+	return nil
+}
+
+func validateOrganizationName(name string) error {
+	if name == "" || len(name) > 100 {
+		return errs.Validation("Name must be 1 to 100 characters long")
+	}
+
+	return nil
+}
+
+func validateType(organizationType OrganizationType) error {
+	switch organizationType {
+	case OrganizationTypeIE, OrganizationTypeLLC, OrganizationTypeJSC:
+		return nil
+	}
+
+	return errs.Validation("Invalid organization type")
+}
+
+func validateRole(role access.Role) error {
+	if !access.ValidRole(role) {
+		return errs.Validation("Invalid role")
+	}
+
+	return nil
+}
+
+func validatePagination(limit int, offset int) error {
+	if limit < 0 || limit > 50 {
+		return errs.Validation("Invalid limit value")
+	}
+
+	if offset < 0 {
+		return errs.Validation("Invalid offset value")
+	}
+
+	return nil
+}
+
+func (s *Service) checkUserExistence(ctx context.Context, username string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
+	}
+
+	userExists, err := s.access.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
+	}
+
+	return nil
+}
+
+func (s *Service) checkPermission(ctx context.Context, username string, permission access.Permission, organizationId string) error {
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return err
+	}
+
+	allowed, err := s.access.Can(ctx, username, permission, organizationId)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return errs.Forbidden("User has no " + string(permission) + " permission in the organization")
+	}
+
+	return nil
+}
+
+// checkNotResponsible enforces that an employee is responsible in only one
+// organization.
+func (s *Service) checkNotResponsible(ctx context.Context, userId string) error {
+	_, err := s.repo.GetResponsible(ctx, userId)
+	if err == nil {
+		return errs.Conflict("User is already responsible for an organization")
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) createResponsible(ctx context.Context, responsible Responsible) error {
+	err := s.repo.CreateResponsible(ctx, responsible)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return errs.Conflict("User is already responsible for an organization")
+	}
+
+	return err
+}
+
+// checkNotLastOwner keeps at least one owner in the organization of
+// responsible, so it can still be managed.
+func (s *Service) checkNotLastOwner(ctx context.Context, responsible Responsible) error {
+	if responsible.Role != access.RoleOwner {
+		return nil
+	}
+
+	owners, err := s.repo.CountResponsibles(ctx, responsible.OrganizationId.String(), access.RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return errs.Conflict("Organization must keep at least one owner")
+	}
+
+	return nil
+}
+
+func (s *Service) getEmployee(ctx context.Context, employeeId string) (Employee, error) {
+	employee, err := s.repo.GetEmployee(ctx, employeeId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return employee, errs.NotFound("Employee not found")
+	}
+
+	return employee, err
+}
+
+func (s *Service) getOrganization(ctx context.Context, organizationId string) (Organization, error) {
+	organization, err := s.repo.GetOrganization(ctx, organizationId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return organization, errs.NotFound("Organization not found")
+	}
+
+	return organization, err
+}
+
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("Organization data was modified concurrently, retry the request")
+	}
+
+	return err
+}
@@ -0,0 +1,63 @@
+package organization
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"github.com/google/uuid"
+	"time"
+)
+
+type OrganizationType string
+
+const (
+	OrganizationTypeIE  OrganizationType = "IE"
+	OrganizationTypeLLC OrganizationType = "LLC"
+	OrganizationTypeJSC OrganizationType = "JSC"
+)
+
+type Organization struct {
+	Id          uuid.UUID        `json:"id"`
+	Name        string           `json:"name" binding:"required"`
+	Description string           `json:"description"`
+	Type        OrganizationType `json:"type" binding:"required"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+type OrganizationPatch struct {
+	Name        string           `json:"name"`
+	Description *string          `json:"description"`
+	Type        OrganizationType `json:"type"`
+}
+
+type Employee struct {
+	Id           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	FirstName    string    `json:"firstName"`
+	LastName     string    `json:"lastName"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Registration creates an employee. The password is optional so employees
+// can still be registered for the username parameter compatibility mode.
+type Registration struct {
+	Username  string `json:"username" binding:"required"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Password  string `json:"password"`
+}
+
+type EmployeePatch struct {
+	FirstName *string `json:"firstName"`
+	LastName  *string `json:"lastName"`
+}
+
+// Responsible is the membership of an employee in an organization. An
+// employee is responsible in at most one organization.
+type Responsible struct {
+	OrganizationId uuid.UUID   `json:"organizationId"`
+	UserId         uuid.UUID   `json:"userId"`
+	Username       string      `json:"username" binding:"required"`
+	Role           access.Role `json:"role" binding:"required"`
+}
@@ -0,0 +1,171 @@
+package organization
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/google/uuid"
+)
+
+// CreateOrganization registers an organization with username as its owner.
+func (s *Service) CreateOrganization(ctx context.Context, username string, organization Organization) (Organization, error) {
+	if err := validateOrganizationName(organization.Name); err != nil {
+		return Organization{}, err
+	}
+
+	if err := validateType(organization.Type); err != nil {
+		return Organization{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Organization{}, err
+	}
+
+	var created Organization
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		userId, err := s.access.UserId(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		if err = s.checkNotResponsible(ctx, userId); err != nil {
+			return err
+		}
+
+		created, err = s.repo.CreateOrganization(ctx, organization)
+		if err != nil {
+			return err
+		}
+
+		return s.createResponsible(ctx, Responsible{
+			OrganizationId: created.Id,
+			UserId:         uuid.MustParse(userId),
+			Username:       username,
+			Role:           access.RoleOwner,
+		})
+	})
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return created, nil
+}
+
+func (s *Service) ListOrganizations(ctx context.Context, username string, limit int, offset int) ([]Organization, error) {
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return nil, err
+	}
+
+	organizations, err := s.repo.ListOrganizations(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Organization{}, organizations...), nil
+}
+
+func (s *Service) GetOrganization(ctx context.Context, organizationId string, username string) (Organization, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Organization{}, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Organization{}, err
+	}
+
+	return s.getOrganization(ctx, organizationId)
+}
+
+func (s *Service) UpdateOrganization(ctx context.Context, organizationId string, username string, patch OrganizationPatch) (Organization, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Organization{}, err
+	}
+
+	if patch.Name != "" {
+		if err := validateOrganizationName(patch.Name); err != nil {
+			return Organization{}, err
+		}
+	}
+
+	if patch.Type != "" {
+		if err := validateType(patch.Type); err != nil {
+			return Organization{}, err
+		}
+	}
+
+	if err := s.checkPermission(ctx, username, access.PermissionOrganizationManage, organizationId); err != nil {
+		return Organization{}, err
+	}
+
+	var updated Organization
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		organization, err := s.getOrganization(ctx, organizationId)
+		if err != nil {
+			return err
+		}
+
+		if patch.Name != "" {
+			organization.Name = patch.Name
+		}
+
+		if patch.Description != nil {
+			organization.Description = *patch.Description
+		}
+
+		if patch.Type != "" {
+			organization.Type = patch.Type
+		}
+
+		updated, err = s.repo.UpdateOrganization(ctx, organization)
+		return err
+	})
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteOrganization removes an organization that has no tenders yet.
+func (s *Service) DeleteOrganization(ctx context.Context, organizationId string, username string) (Organization, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Organization{}, err
+	}
+
+	if err := s.checkPermission(ctx, username, access.PermissionOrganizationManage, organizationId); err != nil {
+		return Organization{}, err
+	}
+
+	var deleted Organization
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		organization, err := s.getOrganization(ctx, organizationId)
+		if err != nil {
+			return err
+		}
+
+		hasTenders, err := s.repo.HasTenders(ctx, organizationId)
+		if err != nil {
+			return err
+		}
+		if hasTenders {
+			return errs.Conflict("Organization has tenders and can't be deleted")
+		}
+
+		if err = s.repo.DeleteOrganization(ctx, organizationId); err != nil {
+			return err
+		}
+
+		deleted = organization
+
+		return nil
+	})
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,31 @@
+package organization
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+)
+
+type Repository interface {
+	repository.Transactor
+	CreateEmployee(ctx context.Context, employee Employee) (Employee, error)
+	GetEmployee(ctx context.Context, employeeId string) (Employee, error)
+	ListEmployees(ctx context.Context, limit int, offset int) ([]Employee, error)
+	UpdateEmployee(ctx context.Context, employee Employee) (Employee, error)
+	// DeleteEmployee fails with repository.ErrInvalidReference while tenders
+	// created by the employee exist.
+	DeleteEmployee(ctx context.Context, employeeId string) error
+	CreateOrganization(ctx context.Context, organization Organization) (Organization, error)
+	GetOrganization(ctx context.Context, organizationId string) (Organization, error)
+	ListOrganizations(ctx context.Context, limit int, offset int) ([]Organization, error)
+	UpdateOrganization(ctx context.Context, organization Organization) (Organization, error)
+	DeleteOrganization(ctx context.Context, organizationId string) error
+	HasTenders(ctx context.Context, organizationId string) (bool, error)
+	CreateResponsible(ctx context.Context, responsible Responsible) error
+	// GetResponsible returns the membership of the employee in any
+	// organization.
+	GetResponsible(ctx context.Context, userId string) (Responsible, error)
+	ListResponsibles(ctx context.Context, organizationId string, limit int, offset int) ([]Responsible, error)
+	DeleteResponsible(ctx context.Context, organizationId string, userId string) error
+	CountResponsibles(ctx context.Context, organizationId string, role access.Role) (int, error)
+}
@@ -0,0 +1,111 @@
+package organization
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"github.com/google/uuid"
+)
+
+func (s *Service) ListResponsibles(ctx context.Context, organizationId string, username string, limit int, offset int) ([]Responsible, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return nil, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPermission(ctx, username, access.PermissionTenderView, organizationId); err != nil {
+		return nil, err
+	}
+
+	responsibles, err := s.repo.ListResponsibles(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Responsible{}, responsibles...), nil
+}
+
+func (s *Service) AddResponsible(ctx context.Context, organizationId string, username string, responsible Responsible) (Responsible, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Responsible{}, err
+	}
+
+	if err := validateRole(responsible.Role); err != nil {
+		return Responsible{}, err
+	}
+
+	if err := s.checkPermission(ctx, username, access.PermissionOrganizationManage, organizationId); err != nil {
+		return Responsible{}, err
+	}
+
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		userId, err := s.access.UserId(ctx, responsible.Username)
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NotFound("Employee not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = s.checkNotResponsible(ctx, userId); err != nil {
+			return err
+		}
+
+		responsible.OrganizationId = uuid.MustParse(organizationId)
+		responsible.UserId = uuid.MustParse(userId)
+
+		return s.createResponsible(ctx, responsible)
+	})
+	if err != nil {
+		return Responsible{}, err
+	}
+
+	return responsible, nil
+}
+
+func (s *Service) RemoveResponsible(ctx context.Context, organizationId string, userId string, username string) (Responsible, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Responsible{}, err
+	}
+
+	if err := validateId(userId, "userId"); err != nil {
+		return Responsible{}, err
+	}
+
+	if err := s.checkPermission(ctx, username, access.PermissionOrganizationManage, organizationId); err != nil {
+		return Responsible{}, err
+	}
+
+	var removed Responsible
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		responsible, err := s.repo.GetResponsible(ctx, userId)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && responsible.OrganizationId.String() != organizationId) {
+			return errs.NotFound("Responsible not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = s.checkNotLastOwner(ctx, responsible); err != nil {
+			return err
+		}
+
+		if err = s.repo.DeleteResponsible(ctx, organizationId, userId); err != nil {
+			return err
+		}
+
+		removed = responsible
+
+		return nil
+	})
+	if err != nil {
+		return Responsible{}, err
+	}
+
+	return removed, nil
+}
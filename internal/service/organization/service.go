@@ -0,0 +1,22 @@
+package organization
+
+import "git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+
+// PasswordHasher validates and hashes the password given on registration.
+type PasswordHasher interface {
+	HashPassword(password string) (string, error)
+}
+
+type Service struct {
+	repo      Repository
+	access    *access.Service
+	passwords PasswordHasher
+}
+
+func NewService(repo Repository, access *access.Service, passwords PasswordHasher) *Service {
+	return &Service{
+		repo:      repo,
+		access:    access,
+		passwords: passwords,
+	}
+}
@@ -0,0 +1,199 @@
+package organization_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/auth"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/organization"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	router *gin.Engine
+}
+
+func newFixture() fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	accessService := access.NewService(memory.NewAccessRepository(store))
+	authService := auth.NewService(memory.NewAuthRepository(store), auth.Config{
+		Secret:        []byte("test-secret"),
+		AccessTTL:     time.Minute,
+		RefreshTTL:    time.Hour,
+		AllowUsername: true,
+	})
+	service := organization.NewService(memory.NewOrganizationRepository(store), accessService, authService)
+	cmd := commands.NewCommander(nil, nil, nil, nil, nil, authService, nil, service, nil)
+
+	router := gin.New()
+	router.Use(cmd.Authenticate)
+	router.POST("/auth/login", cmd.Login)
+	router.POST("/employees", cmd.AddEmployee)
+	router.GET("/employees", cmd.ListEmployees)
+	router.DELETE("/employees/:employeeId", cmd.DeleteEmployee)
+	router.POST("/organizations", cmd.AddOrganization)
+	router.GET("/organizations", cmd.ListOrganizations)
+	router.PATCH("/organizations/:organizationId", cmd.PatchOrganization)
+	router.GET("/organizations/:organizationId/responsibles", cmd.ListResponsibles)
+	router.POST("/organizations/:organizationId/responsibles", cmd.AddResponsible)
+	router.DELETE("/organizations/:organizationId/responsibles/:userId", cmd.DeleteResponsible)
+
+	return fixture{router: router}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(method, target, &payload))
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func (f fixture) register(t *testing.T, username string) organization.Employee {
+	t.Helper()
+
+	var created organization.Employee
+	if code := f.do(t, http.MethodPost, "/employees", gin.H{"username": username, "firstName": "Test"}, &created); code != http.StatusCreated {
+		t.Fatalf("register %s code = %d, want %d", username, code, http.StatusCreated)
+	}
+
+	return created
+}
+
+func (f fixture) createOrganization(t *testing.T, username string) organization.Organization {
+	t.Helper()
+
+	var created organization.Organization
+	body := gin.H{"name": "Org of " + username, "type": organization.OrganizationTypeLLC}
+	if code := f.do(t, http.MethodPost, "/organizations?username="+username, body, &created); code != http.StatusCreated {
+		t.Fatalf("create organization code = %d, want %d", code, http.StatusCreated)
+	}
+
+	return created
+}
+
+func TestRegistrationValidatesInput(t *testing.T) {
+	f := newFixture()
+
+	if code := f.do(t, http.MethodPost, "/employees", gin.H{"username": "has space"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid username code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := f.do(t, http.MethodPost, "/employees", gin.H{"username": "user", "password": "short"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("short password code = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	if code := f.do(t, http.MethodPost, "/employees", gin.H{"username": "user", "password": "correct horse battery"}, nil); code != http.StatusCreated {
+		t.Fatalf("register code = %d, want %d", code, http.StatusCreated)
+	}
+	if code := f.do(t, http.MethodPost, "/employees", gin.H{"username": "user"}, nil); code != http.StatusConflict {
+		t.Fatalf("taken username code = %d, want %d", code, http.StatusConflict)
+	}
+	if code := f.do(t, http.MethodPost, "/auth/login", gin.H{"username": "user", "password": "correct horse battery"}, nil); code != http.StatusOK {
+		t.Fatalf("login code = %d, want %d", code, http.StatusOK)
+	}
+
+	f.register(t, "another")
+	var employees []organization.Employee
+	if code := f.do(t, http.MethodGet, "/employees?username=user&limit=1&offset=1", nil, &employees); code != http.StatusOK {
+		t.Fatalf("list code = %d, want %d", code, http.StatusOK)
+	}
+	if len(employees) != 1 || employees[0].Username != "user" {
+		t.Fatalf("employees = %+v, want the second one by username", employees)
+	}
+	if code := f.do(t, http.MethodGet, "/employees?username=user&limit=51", nil, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid limit code = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestUserIsResponsibleInOneOrganization(t *testing.T) {
+	f := newFixture()
+	f.register(t, "owner")
+	member := f.register(t, "member")
+	f.register(t, "other")
+
+	created := f.createOrganization(t, "owner")
+	target := "/organizations/" + created.Id.String() + "/responsibles"
+
+	if code := f.do(t, http.MethodPost, "/organizations?username=owner", gin.H{"name": "Second", "type": organization.OrganizationTypeJSC}, nil); code != http.StatusConflict {
+		t.Fatalf("second organization code = %d, want %d", code, http.StatusConflict)
+	}
+
+	body := gin.H{"username": "member", "role": access.RoleEvaluator}
+	if code := f.do(t, http.MethodPost, target+"?username=member", body, nil); code != http.StatusForbidden {
+		t.Fatalf("add by non member code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodPost, target+"?username=owner", gin.H{"username": "member", "role": "Admin"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid role code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := f.do(t, http.MethodPost, target+"?username=owner", body, nil); code != http.StatusCreated {
+		t.Fatalf("add responsible code = %d, want %d", code, http.StatusCreated)
+	}
+
+	other := f.createOrganization(t, "other")
+	if code := f.do(t, http.MethodPost, "/organizations/"+other.Id.String()+"/responsibles?username=other", body, nil); code != http.StatusConflict {
+		t.Fatalf("add to second organization code = %d, want %d", code, http.StatusConflict)
+	}
+
+	var responsibles []organization.Responsible
+	if code := f.do(t, http.MethodGet, target+"?username=member", nil, &responsibles); code != http.StatusOK {
+		t.Fatalf("list code = %d, want %d", code, http.StatusOK)
+	}
+	if len(responsibles) != 2 || responsibles[0].Username != "member" || responsibles[1].Role != access.RoleOwner {
+		t.Fatalf("responsibles = %+v, want member and owner", responsibles)
+	}
+
+	if code := f.do(t, http.MethodPatch, "/organizations/"+created.Id.String()+"?username=member", gin.H{"name": "Renamed"}, nil); code != http.StatusForbidden {
+		t.Fatalf("patch by evaluator code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := f.do(t, http.MethodDelete, target+"/"+member.Id.String()+"?username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("remove responsible code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodPost, "/organizations/"+other.Id.String()+"/responsibles?username=other", body, nil); code != http.StatusCreated {
+		t.Fatalf("add after removal code = %d, want %d", code, http.StatusCreated)
+	}
+}
+
+func TestLastOwnerStays(t *testing.T) {
+	f := newFixture()
+	owner := f.register(t, "owner")
+	created := f.createOrganization(t, "owner")
+
+	if code := f.do(t, http.MethodDelete, "/organizations/"+created.Id.String()+"/responsibles/"+owner.Id.String()+"?username=owner", nil, nil); code != http.StatusConflict {
+		t.Fatalf("remove last owner code = %d, want %d", code, http.StatusConflict)
+	}
+	if code := f.do(t, http.MethodDelete, "/employees/"+owner.Id.String()+"?username=owner", nil, nil); code != http.StatusConflict {
+		t.Fatalf("delete last owner code = %d, want %d", code, http.StatusConflict)
+	}
+
+	f.register(t, "partner")
+	body := gin.H{"username": "partner", "role": access.RoleOwner}
+	if code := f.do(t, http.MethodPost, "/organizations/"+created.Id.String()+"/responsibles?username=owner", body, nil); code != http.StatusCreated {
+		t.Fatalf("add owner code = %d, want %d", code, http.StatusCreated)
+	}
+	if code := f.do(t, http.MethodDelete, "/employees/"+owner.Id.String()+"?username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("delete owner code = %d, want %d", code, http.StatusOK)
+	}
+}
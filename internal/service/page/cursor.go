@@ -0,0 +1,69 @@
+package page
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"strings"
+)
+
+// Codec turns keys into opaque cursors signed with HMAC-SHA256, so clients
+// can't forge positions. A cursor is bound to the scope of the list it was
+// issued for, the route and its filters, and is rejected by any other list.
+type Codec struct {
+	secret []byte
+}
+
+type cursor struct {
+	Key
+	Scope string `json:"s"`
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{
+		secret: secret,
+	}
+}
+
+func (c *Codec) Encode(scope string, key Key) string {
+	payload, _ := json.Marshal(cursor{Key: key, Scope: scope})
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+func (c *Codec) Decode(scope string, encoded string) (Key, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(encoded, ".")
+	if !found {
+		return Key{}, errs.Validation("Invalid cursor")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Key{}, errs.Validation("Invalid cursor")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return Key{}, errs.Validation("Invalid cursor")
+	}
+
+	var decoded cursor
+	if err = json.Unmarshal(payload, &decoded); err != nil {
+		return Key{}, errs.Validation("Invalid cursor")
+	}
+
+	if decoded.Scope != scope {
+		return Key{}, errs.Validation("Cursor belongs to another list")
+	}
+
+	return decoded.Key, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,48 @@
+package page
+
+import "github.com/google/uuid"
+
+// Key is the keyset position of a list item. Lists are ordered by name and
+// then id, so the pair identifies where the next page starts.
+type Key struct {
+	Name string    `json:"n"`
+	Id   uuid.UUID `json:"i"`
+}
+
+// Request selects a page either by offset or, when After is set, by the key of
+// the last item of the previous page.
+type Request struct {
+	Limit  int
+	Offset int
+	After  *Key
+}
+
+// Lookahead asks for one more item than requested, which tells New whether a
+// next page exists.
+func (r Request) Lookahead() Request {
+	r.Limit++
+	return r
+}
+
+type Page[T any] struct {
+	Items []T
+	Total int
+	Next  *Key
+}
+
+// New builds a page from items fetched with Request.Lookahead. A zero limit
+// yields no items, only the total.
+func New[T any](items []T, limit int, total int, key func(T) Key) Page[T] {
+	p := Page[T]{Items: append([]T{}, items...), Total: total}
+
+	if len(p.Items) > limit {
+		p.Items = p.Items[:limit]
+	}
+
+	if limit > 0 && len(items) > limit {
+		next := key(p.Items[limit-1])
+		p.Next = &next
+	}
+
+	return p
+}
@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"github.com/google/uuid"
+	"slices"
+)
+
+func validateFilter(filter Filter) error {
+	if filter.OrganizationId != "" {
+		if _, err := uuid.Parse(filter.OrganizationId); err != nil {
+			return errs.Validation("Invalid organizationId")
+		}
+	}
+
+	if filter.TenderId != "" {
+		if _, err := uuid.Parse(filter.TenderId); err != nil {
+			return errs.Validation("Invalid tenderId")
+		}
+	}
+
+	for _, eventType := range filter.Types {
+		if !event.Known(eventType) {
+			return errs.Validation("Unsupported event type " + string(eventType))
+		}
+	}
+
+	return nil
+}
+
+func validateLastEventId(lastEventId int64) error {
+	if lastEventId < FromNow {
+		return errs.Validation("Invalid Last-Event-ID")
+	}
+
+	return nil
+}
+
+func (s *Service) checkUserExistence(ctx context.Context, username string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
+	}
+
+	userExists, err := s.access.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
+	}
+
+	return nil
+}
+
+// canView reports whether username may see the events of the organization,
+// which carry bid details.
+func (s *Service) canView(ctx context.Context, username string, organizationId string) (bool, error) {
+	return s.access.Can(ctx, username, access.PermissionBidView, organizationId)
+}
+
+func (s *Service) checkViewer(ctx context.Context, username string, organizationId string) error {
+	allowed, err := s.canView(ctx, username, organizationId)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return errs.Forbidden("User can't view the organization")
+	}
+
+	return nil
+}
+
+func matches(filter Filter, e event.Event) bool {
+	if filter.OrganizationId != "" && e.OrganizationId.String() != filter.OrganizationId {
+		return false
+	}
+
+	if filter.TenderId != "" && e.TenderId.String() != filter.TenderId {
+		return false
+	}
+
+	return len(filter.Types) == 0 || slices.Contains(filter.Types, e.Type)
+}
@@ -0,0 +1,13 @@
+package stream
+
+import "git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+
+// FromNow starts a stream with live events only, skipping the event log.
+const FromNow int64 = -1
+
+// Filter narrows a stream down. Empty fields match everything.
+type Filter struct {
+	OrganizationId string
+	TenderId       string
+	Types          []event.Type
+}
@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"time"
+)
+
+type Service struct {
+	log       event.Log
+	broker    *event.Broker
+	access    *access.Service
+	accessTTL time.Duration
+}
+
+// NewService creates the stream service. Open streams check again whether
+// their user may view an organization once accessTTL has passed since the
+// last check.
+func NewService(log event.Log, broker *event.Broker, access *access.Service, accessTTL time.Duration) *Service {
+	return &Service{
+		log:       log,
+		broker:    broker,
+		access:    access,
+		accessTTL: accessTTL,
+	}
+}
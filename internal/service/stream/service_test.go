@@ -0,0 +1,274 @@
+package stream_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/stream"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	server         *httptest.Server
+	store          *memory.Store
+	outbox         *memory.OutboxRepository
+	relay          *event.Relay
+	organizationId uuid.UUID
+	otherId        uuid.UUID
+}
+
+func newFixture(t *testing.T, accessTTL time.Duration) fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	otherId := store.AddOrganization("other")
+	store.AddResponsible(organizationId, store.AddEmployee("owner"))
+	store.AddResponsible(otherId, store.AddEmployee("competitor"))
+
+	outbox := memory.NewOutboxRepository(store)
+	broker := event.NewBroker()
+	service := stream.NewService(outbox, broker, access.NewService(memory.NewAccessRepository(store)), accessTTL)
+	cmd := commands.NewCommander(nil, nil, nil, nil, service, nil, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/events/stream", cmd.EventStream)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return fixture{
+		server:         server,
+		store:          store,
+		outbox:         outbox,
+		relay:          event.NewRelay(outbox, event.NewBus(), broker),
+		organizationId: uuid.MustParse(organizationId),
+		otherId:        uuid.MustParse(otherId),
+	}
+}
+
+func (f fixture) publish(t *testing.T, eventType event.Type, organizationId uuid.UUID) {
+	t.Helper()
+
+	f.publishEvent(t, event.Event{Type: eventType, OrganizationId: organizationId, TenderId: uuid.New()})
+}
+
+func (f fixture) publishEvent(t *testing.T, e event.Event) {
+	t.Helper()
+
+	e.Payload = json.RawMessage(`{}`)
+	if err := f.outbox.Append(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.relay.Deliver(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (f fixture) open(t *testing.T, query string, lastEventId string) *http.Response {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, f.server.URL+"/events/stream?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventId != "" {
+		request.Header.Set("Last-Event-ID", lastEventId)
+	}
+
+	response, err := f.server.Client().Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+
+	return response
+}
+
+// readIds reads the ids of the next n events of an open stream.
+func readIds(t *testing.T, scanner *bufio.Scanner, n int) []string {
+	t.Helper()
+
+	var ids []string
+	for len(ids) < n && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id:"); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) < n {
+		t.Fatalf("ids = %v, stream ended: %v", ids, scanner.Err())
+	}
+
+	return ids
+}
+
+func TestStreamRequiresResponsible(t *testing.T) {
+	f := newFixture(t, time.Minute)
+
+	if response := f.open(t, "", ""); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("anonymous code = %d, want %d", response.StatusCode, http.StatusUnauthorized)
+	}
+
+	if response := f.open(t, "username=competitor&organizationId="+f.organizationId.String(), ""); response.StatusCode != http.StatusForbidden {
+		t.Fatalf("competitor code = %d, want %d", response.StatusCode, http.StatusForbidden)
+	}
+
+	if response := f.open(t, "username=owner", "abc"); response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid Last-Event-ID code = %d, want %d", response.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestStreamResumesFromLastEventId(t *testing.T) {
+	f := newFixture(t, time.Minute)
+
+	f.publish(t, event.TenderCreated, f.organizationId)
+	f.publish(t, event.TenderPublished, f.organizationId)
+	f.publish(t, event.BidCreated, f.organizationId)
+	f.publish(t, event.TenderCreated, f.otherId)
+
+	response := f.open(t, "username=owner", "1")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stream code = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+	scanner := bufio.NewScanner(response.Body)
+
+	if ids := readIds(t, scanner, 1); ids[0] != "2" {
+		t.Fatalf("replayed ids = %v, want [2]", ids)
+	}
+
+	f.publish(t, event.TenderClosed, f.otherId)
+	f.publish(t, event.TenderClosed, f.organizationId)
+
+	if ids := readIds(t, scanner, 1); ids[0] != "6" {
+		t.Fatalf("live ids = %v, want [6]", ids)
+	}
+}
+
+func TestStreamFiltersByType(t *testing.T) {
+	f := newFixture(t, time.Minute)
+
+	response := f.open(t, "username=owner&type=TenderClosed,BidPublished", "")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stream code = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+	scanner := bufio.NewScanner(response.Body)
+
+	f.publish(t, event.TenderPublished, f.organizationId)
+	f.publish(t, event.BidPublished, f.organizationId)
+
+	if ids := readIds(t, scanner, 1); ids[0] != "2" {
+		t.Fatalf("ids = %v, want [2]", ids)
+	}
+}
+
+func TestStreamResumesInPublicationOrder(t *testing.T) {
+	f := newFixture(t, time.Minute)
+
+	for _, eventType := range []event.Type{event.TenderCreated, event.TenderPublished} {
+		err := f.outbox.Append(context.Background(), event.Event{Type: eventType, OrganizationId: f.organizationId, TenderId: uuid.New(), Payload: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Event 2 commits first, so a client that saw it misses nothing once
+	// event 1 is published after it.
+	if _, err := f.outbox.MarkPublished(context.Background(), []int64{2}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.relay.Deliver(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	response := f.open(t, "username=owner", "1")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stream code = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+	scanner := bufio.NewScanner(response.Body)
+
+	if ids := readIds(t, scanner, 1); ids[0] != "2" {
+		t.Fatalf("replayed ids = %v, want [2]", ids)
+	}
+
+	var e event.Event
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	if e.Id != 1 || e.Type != event.TenderCreated {
+		t.Fatalf("replayed event = %+v, want event 1", e)
+	}
+}
+
+func TestStreamRechecksAccess(t *testing.T) {
+	f := newFixture(t, 0)
+
+	viewerId := f.store.AddEmployee("viewer")
+	f.store.AddMember(f.organizationId.String(), viewerId, access.RoleViewer)
+	f.store.AddMember(f.otherId.String(), viewerId, access.RoleViewer)
+
+	response := f.open(t, "username=viewer", "")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stream code = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+	scanner := bufio.NewScanner(response.Body)
+
+	f.publish(t, event.TenderCreated, f.organizationId)
+	if ids := readIds(t, scanner, 1); ids[0] != "1" {
+		t.Fatalf("ids = %v, want [1]", ids)
+	}
+
+	err := memory.NewOrganizationRepository(f.store).DeleteResponsible(context.Background(), f.organizationId.String(), viewerId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.publish(t, event.TenderClosed, f.organizationId)
+	f.publish(t, event.TenderClosed, f.otherId)
+	if ids := readIds(t, scanner, 1); ids[0] != "3" {
+		t.Fatalf("ids after losing access = %v, want [3]", ids)
+	}
+}
+
+func TestStreamShowsPublicAndAuthoredEvents(t *testing.T) {
+	f := newFixture(t, time.Minute)
+	bidderId := f.store.AddEmployee("bidder")
+
+	response := f.open(t, "username=bidder", "")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stream code = %d, want %d", response.StatusCode, http.StatusOK)
+	}
+	scanner := bufio.NewScanner(response.Body)
+
+	tenderId := uuid.New()
+	bidId := uuid.New()
+	f.publishEvent(t, event.Event{Type: event.TenderCreated, OrganizationId: f.organizationId, TenderId: tenderId})
+	f.publishEvent(t, event.Event{Type: event.TenderPublished, OrganizationId: f.organizationId, TenderId: tenderId, Public: true})
+	f.publishEvent(t, event.Event{Type: event.BidCreated, OrganizationId: f.organizationId, TenderId: tenderId, BidId: &bidId, AuthorType: "User", AuthorId: uuid.NewString()})
+	f.publishEvent(t, event.Event{Type: event.BidCreated, OrganizationId: f.organizationId, TenderId: tenderId, BidId: &bidId, AuthorType: "User", AuthorId: bidderId})
+	f.publishEvent(t, event.Event{Type: event.BidCreated, OrganizationId: f.otherId, TenderId: tenderId, BidId: &bidId, AuthorType: "Organization", AuthorId: f.otherId.String()})
+	f.publishEvent(t, event.Event{Type: event.TenderClosed, OrganizationId: f.organizationId, TenderId: tenderId, Public: true})
+
+	if ids := readIds(t, scanner, 3); strings.Join(ids, ",") != "2,4,6" {
+		t.Fatalf("ids = %v, want [2 4 6]", ids)
+	}
+}
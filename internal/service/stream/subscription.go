@@ -0,0 +1,205 @@
+package stream
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"log"
+	"time"
+)
+
+const replayBatchSize = 100
+
+// Subscription delivers the events its user may see in order. Its channel is
+// closed when the subscription ends, including when it falls too far behind;
+// the client is then expected to reconnect with the last id it received.
+type Subscription struct {
+	events chan event.Event
+	cancel context.CancelFunc
+}
+
+func (sub *Subscription) Events() <-chan event.Event {
+	return sub.events
+}
+
+func (sub *Subscription) Close() {
+	sub.cancel()
+}
+
+type stream struct {
+	service    *Service
+	username   string
+	filter     Filter
+	live       <-chan event.Event
+	out        chan event.Event
+	last       int64
+	authorized map[string]authorization
+}
+
+// authorization remembers an access check of the user until it is made again
+// after the service's access TTL.
+type authorization struct {
+	allowed   bool
+	checkedAt time.Time
+}
+
+// Open starts a stream of the events username may see: those of organizations
+// whose bids they can view, changes to published tenders, and events about bids
+// they author. Events with sequences after lastEventId are first replayed from
+// the event log, unless it is FromNow. The stream runs until ctx is done or the subscription is
+// closed.
+func (s *Service) Open(ctx context.Context, username string, filter Filter, lastEventId int64) (*Subscription, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if err := validateLastEventId(lastEventId); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return nil, err
+	}
+
+	if filter.OrganizationId != "" {
+		if err := s.checkViewer(ctx, username, filter.OrganizationId); err != nil {
+			return nil, err
+		}
+	}
+
+	// Subscribe before reading the log, so no event published in between is
+	// lost; events seen twice are skipped by sequence.
+	live, unsubscribe := s.broker.Subscribe()
+	ctx, cancel := context.WithCancel(ctx)
+
+	st := &stream{
+		service:    s,
+		username:   username,
+		filter:     filter,
+		live:       live,
+		out:        make(chan event.Event),
+		last:       lastEventId,
+		authorized: make(map[string]authorization),
+	}
+
+	go func() {
+		defer close(st.out)
+		defer unsubscribe()
+
+		if err := st.run(ctx); err != nil && ctx.Err() == nil {
+			log.Println("Error streaming events:", err)
+		}
+	}()
+
+	return &Subscription{events: st.out, cancel: cancel}, nil
+}
+
+func (st *stream) run(ctx context.Context) error {
+	if st.last != FromNow {
+		if err := st.replay(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-st.live:
+			if !ok {
+				return nil
+			}
+
+			if st.last != FromNow && e.Sequence <= st.last {
+				continue
+			}
+
+			if err := st.send(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (st *stream) replay(ctx context.Context) error {
+	for {
+		events, err := st.service.log.ListAfter(ctx, st.last, replayBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err = st.send(ctx, e); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < replayBatchSize {
+			return nil
+		}
+	}
+}
+
+func (st *stream) send(ctx context.Context, e event.Event) error {
+	st.last = e.Sequence
+
+	if !matches(st.filter, e) {
+		return nil
+	}
+
+	visible, err := st.visible(ctx, e)
+	if err != nil || !visible {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case st.out <- e:
+		return nil
+	}
+}
+
+// visible reports whether the user may see e: as a member who can view the
+// organization's bids, because e is public, or as the author of its bid.
+func (st *stream) visible(ctx context.Context, e event.Event) (bool, error) {
+	if event.OwnerVisible(e.Type) {
+		allowed, err := st.allowed("organization:"+e.OrganizationId.String(), func() (bool, error) {
+			return st.service.canView(ctx, st.username, e.OrganizationId.String())
+		})
+		if err != nil || allowed {
+			return allowed, err
+		}
+	}
+
+	if e.Public {
+		return true, nil
+	}
+
+	if event.AuthorVisible(e.Type) && e.AuthorType != "" {
+		return st.allowed("author:"+e.AuthorType+":"+e.AuthorId, func() (bool, error) {
+			return st.service.access.IsAuthor(ctx, st.username, e.AuthorType, e.AuthorId)
+		})
+	}
+
+	return false, nil
+}
+
+// allowed returns the cached answer of check under key, checking again once it
+// is older than the access TTL, so a user who loses access stops receiving
+// events on an open stream.
+func (st *stream) allowed(key string, check func() (bool, error)) (bool, error) {
+	now := time.Now()
+
+	cached, ok := st.authorized[key]
+	if ok && now.Sub(cached.checkedAt) < st.service.accessTTL {
+		return cached.allowed, nil
+	}
+
+	allowed, err := check()
+	if err != nil {
+		return false, err
+	}
+	st.authorized[key] = authorization{allowed: allowed, checkedAt: now}
+
+	return allowed, nil
+}
@@ -1,51 +1,51 @@
 package tender
 
 import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"time"
 )
 
-func (s *Service) Add(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+func (s *Service) Add(ctx context.Context, tender Tender) (Tender, error) {
+	if err := validateServiceType(tender.ServiceType); err != nil {
+		return Tender{}, err
 	}
 
-	var tender Tender
-	if err = ctx.ShouldBindJSON(&tender); err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid request data"})
-		return
+	tender.PublishAt, tender.BidDeadline, tender.CloseAt = utc(tender.PublishAt), utc(tender.BidDeadline), utc(tender.CloseAt)
+	if err := validateFuture(time.Now().UTC(), tender.PublishAt, tender.BidDeadline, tender.CloseAt); err != nil {
+		return Tender{}, err
 	}
 
-	tender, ok := insertTender(tx, ctx, tender)
-	if !ok {
-		return
+	if err := validateScheduleOrder(tender); err != nil {
+		return Tender{}, err
 	}
 
-	if !insertTenderDiff(tx, ctx, tender) {
-		return
+	if err := s.checkUserExistence(ctx, tender.CreatorUsername); err != nil {
+		return Tender{}, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+	if err := s.checkPermission(ctx, tender.CreatorUsername, access.PermissionTenderCreate, tender.OrganizationId.String()); err != nil {
+		return Tender{}, err
 	}
 
-	returningTender := Tender{
-		Id:              tender.Id,
-		Name:            tender.Name,
-		Description:     tender.Description,
-		ServiceType:     tender.ServiceType,
-		Status:          TenderStatusCreated,
-		OrganizationId:  tender.OrganizationId,
-		CreatorUsername: tender.CreatorUsername,
-		Version:         1,
-		CreatedAt:       tender.CreatedAt,
+	var created Tender
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.insertTender(ctx, tender)
+		if err != nil {
+			return err
+		}
+
+		if err = s.insertDiff(ctx, created); err != nil {
+			return err
+		}
+
+		return s.record(ctx, event.TenderCreated, created, false)
+	})
+	if err != nil {
+		return Tender{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusCreated, returningTender)
+	return created, nil
 }
@@ -0,0 +1,64 @@
+package tender
+
+import "context"
+
+func (s *Service) Diff(ctx context.Context, tenderId string, username string, from int, to int) (TenderDiff, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return TenderDiff{}, err
+	}
+
+	if err := validateVersion(from); err != nil {
+		return TenderDiff{}, err
+	}
+
+	if err := validateVersion(to); err != nil {
+		return TenderDiff{}, err
+	}
+
+	tender, err := s.getTenderById(ctx, tenderId)
+	if err != nil {
+		return TenderDiff{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, tender); err != nil {
+		return TenderDiff{}, err
+	}
+
+	fromTender, err := s.getTenderByIdAndVersion(ctx, tenderId, from)
+	if err != nil {
+		return TenderDiff{}, err
+	}
+
+	toTender, err := s.getTenderByIdAndVersion(ctx, tenderId, to)
+	if err != nil {
+		return TenderDiff{}, err
+	}
+
+	for _, version := range []Tender{fromTender, toTender} {
+		if err = s.checkVersionVisible(ctx, username, version); err != nil {
+			return TenderDiff{}, err
+		}
+	}
+
+	return TenderDiff{From: from, To: to, Changes: diffTenders(fromTender, toTender)}, nil
+}
+
+func diffTenders(from Tender, to Tender) []FieldChange {
+	changes := []FieldChange{}
+
+	for _, field := range []struct {
+		name     string
+		from, to string
+	}{
+		{"name", from.Name, to.Name},
+		{"description", from.Description, to.Description},
+		{"serviceType", string(from.ServiceType), string(to.ServiceType)},
+		{"status", string(from.Status), string(to.Status)},
+	} {
+		if field.from != field.to {
+			changes = append(changes, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+
+	return changes
+}
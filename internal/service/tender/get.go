@@ -0,0 +1,22 @@
+package tender
+
+import "context"
+
+// Get returns the current version of a tender. Published tenders are visible
+// to everyone, others only to the organization's members.
+func (s *Service) Get(ctx context.Context, tenderId string, username string) (Tender, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.getTenderById(ctx, tenderId)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, tender); err != nil {
+		return Tender{}, err
+	}
+
+	return tender, nil
+}
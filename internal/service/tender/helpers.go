@@ -1,217 +1,255 @@
 package tender
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
 	"strconv"
+	"time"
 )
 
-func validateServiceType(serviceType string) error {
-	switch TenderServiceType(serviceType) {
+func validateServiceType(serviceType TenderServiceType) error {
+	switch serviceType {
 	case TenderServiceTypeDelivery, TenderServiceTypeConstruction, TenderServiceTypeManufacture:
 		return nil
 	}
 
-	return errors.New("invalid service type")
+	return errs.Validation("Invalid service type value")
 }
 
-func validateStatus(status string) error {
-	switch TenderStatus(status) {
+func validateStatus(status TenderStatus) error {
+	switch status {
 	case TenderStatusCreated, TenderStatusPublished, TenderStatusClosed:
 		return nil
 	}
 
-	return errors.New("invalid status")
+	return errs.Validation("Invalid status")
 }
 
-func checkUserExistence(db *sql.DB, ctx *gin.Context, username string) bool {
-	query := `SELECT EXISTS(SELECT 1 FROM employee WHERE username = $1)`
+func validateFuture(now time.Time, times ...*time.Time) error {
+	for _, at := range times {
+		if at != nil && !at.After(now) {
+			return errs.Validation("Schedule times must be in the future")
+		}
+	}
 
-	var userExists bool
+	return nil
+}
 
-	err := db.QueryRow(query, username).Scan(&userExists)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return false
+func validateScheduleOrder(tender Tender) error {
+	if tender.PublishAt != nil && tender.BidDeadline != nil && !tender.PublishAt.Before(*tender.BidDeadline) {
+		return errs.Validation("publishAt must be before bidDeadline")
 	}
-	if !userExists {
-		ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"reason": "Unauthorized user"})
-		return false
+
+	if tender.PublishAt != nil && tender.CloseAt != nil && !tender.PublishAt.Before(*tender.CloseAt) {
+		return errs.Validation("publishAt must be before closeAt")
 	}
 
-	return true
-}
+	if tender.BidDeadline != nil && tender.CloseAt != nil && tender.BidDeadline.After(*tender.CloseAt) {
+		return errs.Validation("bidDeadline must not be after closeAt")
+	}
 
-func extractTenders(ctx *gin.Context, rows *sql.Rows) ([]Tender, bool) {
-	var tenders []Tender
+	return nil
+}
 
-	for rows.Next() {
-		var t Tender
-		err := rows.Scan(&t.Id, &t.Name, &t.Description, &t.Status, &t.ServiceType, &t.Version, &t.OrganizationId, &t.CreatorUsername, &t.CreatedAt)
-		if err != nil {
-			ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Tenders not found"})
-			return nil, false
-		}
-		tenders = append(tenders, t)
+func utc(at *time.Time) *time.Time {
+	if at == nil {
+		return nil
 	}
 
-	return tenders, true
+	u := at.UTC()
+	return &u
 }
 
-func checkVersionAndUsername(tx *sql.Tx, ctx *gin.Context, version int, username string, tenderId string) (int, bool) {
-	query := "SELECT version, creator_username FROM tender WHERE id = $1"
+func validateTenderId(tenderId string) error {
+	if tenderId == "" || len(tenderId) > 100 {
+		return errs.Validation("Invalid tenderId")
+	}
 
-	var currentVersion int
-	var creatorUsername string
+	return nil
+}
 
-	err := tx.QueryRowContext(ctx, query, tenderId).Scan(&currentVersion, &creatorUsername)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
-		return 0, false
+func validateVersion(version int) error {
+	if version < 1 {
+		return errs.Validation("Version must be >= 1")
 	}
 
-	if version >= currentVersion {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "No such a version. Latest version is " + strconv.Itoa(currentVersion)})
-		return 0, false
+	return nil
+}
+
+func validatePagination(limit int, offset int) error {
+	if limit < 0 || limit > 50 {
+		return errs.Validation("Invalid limit value")
 	}
 
-	if username != creatorUsername {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return 0, false
+	if offset < 0 {
+		return errs.Validation("Invalid offset value")
 	}
 
-	return currentVersion, true
+	return nil
 }
 
-func insertTender(tx *sql.Tx, ctx *gin.Context, tender Tender) (Tender, bool) {
-	query := "INSERT INTO tender (name, description, status, service_type, version, organization_id, creator_username) VALUES ($1, $2, $3, $4, 1, $5, $6) RETURNING id, created_at"
+// validatePage checks a page request, which continues after a cursor or skips
+// an offset but not both.
+func validatePage(request page.Request) error {
+	if err := validatePagination(request.Limit, request.Offset); err != nil {
+		return err
+	}
 
-	err := tx.QueryRowContext(ctx, query, tender.Name, tender.Description, TenderStatusCreated, tender.ServiceType, tender.OrganizationId, tender.CreatorUsername).Scan(&tender.Id, &tender.CreatedAt)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("err: %v, rollbackErr: %v", err, rollbackErr)})
-			return tender, false
-		}
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid organizationId or creatorUsername"})
-		return tender, false
+	if request.After != nil && request.Offset != 0 {
+		return errs.Validation("Use either offset or cursor")
 	}
 
-	return tender, true
+	return nil
 }
 
-func insertTenderDiff(tx *sql.Tx, ctx *gin.Context, tender Tender) bool {
-	query := "INSERT INTO tender_diff (id, name, description, status, service_type, version, organization_id, creator_username, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"
-
-	if tender.Status == "" {
-		tender.Status = TenderStatusCreated
+func (s *Service) checkUserExistence(ctx context.Context, username string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
 	}
 
-	_, err := tx.ExecContext(ctx, query, tender.Id, tender.Name, tender.Description, tender.Status, tender.ServiceType, tender.Version+1, tender.OrganizationId, tender.CreatorUsername, tender.CreatedAt)
+	userExists, err := s.access.UserExists(ctx, username)
 	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Failed to rollback: %v", rollbackErr)})
-			return false
-		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return false
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
 	}
 
-	return true
+	return nil
 }
 
-func getTenderById(tx *sql.Tx, ctx *gin.Context, tenderId string) (Tender, bool) {
-	query := "SELECT * FROM tender WHERE id = $1"
+func (s *Service) checkPermission(ctx context.Context, username string, permission access.Permission, organizationId string) error {
+	allowed, err := s.access.Can(ctx, username, permission, organizationId)
+	if err != nil {
+		return err
+	}
 
-	var tender Tender
+	if !allowed {
+		return errs.Forbidden("User has no " + string(permission) + " permission in the organization")
+	}
 
-	err := tx.QueryRowContext(ctx, query, tenderId).Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.Version, &tender.OrganizationId, &tender.CreatorUsername, &tender.CreatedAt)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
-		return tender, false
+	return nil
+}
+
+func checkVersion(tender Tender, version int) error {
+	if version >= tender.Version {
+		return errs.Validation("No such a version. Latest version is " + strconv.Itoa(tender.Version))
 	}
 
-	return tender, true
+	return nil
 }
 
-func getTenderByIdAndVersion(tx *sql.Tx, ctx *gin.Context, tenderId string, version int) (Tender, bool) {
-	query := "SELECT * FROM tender_diff WHERE id = $1 AND version = $2"
+func checkExpectedVersion(tender Tender, expectedVersion int) error {
+	if expectedVersion != 0 && tender.Version != expectedVersion {
+		return errs.PreconditionFailed("Tender was modified, current version is " + strconv.Itoa(tender.Version))
+	}
 
-	var tender Tender
+	return nil
+}
 
-	err := tx.QueryRowContext(ctx, query, tenderId, version).Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.Version, &tender.OrganizationId, &tender.CreatorUsername, &tender.CreatedAt)
-	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Version not found"})
-		return tender, false
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("Tender was modified concurrently, retry the request")
 	}
 
-	return tender, true
+	return err
 }
 
-func getTenderId(ctx *gin.Context) (string, bool) {
-	tenderId := ctx.Param("tenderId")
+func (s *Service) insertTender(ctx context.Context, tender Tender) (Tender, error) {
+	tender.Status = TenderStatusCreated
+	tender.Version = 1
 
-	if tenderId == "" || len(tenderId) > 100 {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid tenderId"})
-		return "", false
+	tender, err := s.repo.Create(ctx, tender)
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return tender, errs.Validation("Invalid organizationId or creatorUsername")
 	}
 
-	return tenderId, true
+	return tender, err
 }
 
-func getUsername(ctx *gin.Context) (string, bool) {
-	username := ctx.Query("username")
-
-	if username == "" {
-		ctx.IndentedJSON(http.StatusUnauthorized, gin.H{"reason": "Username is required"})
-		return "", false
+func (s *Service) saveTender(ctx context.Context, tender Tender) error {
+	if err := s.repo.Update(ctx, tender); err != nil {
+		return err
 	}
 
-	return username, true
+	return s.insertDiff(ctx, tender)
 }
 
-func getLimit(ctx *gin.Context) (int, bool) {
-	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+func (s *Service) insertDiff(ctx context.Context, tender Tender) error {
+	err := s.repo.InsertDiff(ctx, tender)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return errs.Conflict("Tender version " + strconv.Itoa(tender.Version) + " is already recorded")
+	}
+
+	return err
+}
 
-	if err != nil || limit < 0 || limit > 50 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid limit value"})
-		return 0, false
+// record appends an event about tender, which is public when everyone could see
+// the tender before or after the change.
+func (s *Service) record(ctx context.Context, eventType event.Type, tender Tender, public bool) error {
+	e, err := event.New(eventType, tender.OrganizationId, tender.Id, tender)
+	if err != nil {
+		return err
 	}
+	e.Public = public
 
-	return limit, true
+	return s.events.Append(ctx, e)
 }
 
-func getOffset(ctx *gin.Context) (int, bool) {
-	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+func (s *Service) getTenderById(ctx context.Context, tenderId string) (Tender, error) {
+	tender, err := s.repo.Get(ctx, tenderId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return tender, errs.NotFound("Tender not found")
+	}
+
+	return tender, err
+}
 
-	if err != nil || offset < 0 || offset > 1<<31-1 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid offset value"})
-		return 0, false
+func (s *Service) getTenderByIdAndVersion(ctx context.Context, tenderId string, version int) (Tender, error) {
+	tender, err := s.repo.GetVersion(ctx, tenderId, version)
+	if errors.Is(err, repository.ErrNotFound) {
+		return tender, errs.NotFound("Version not found")
 	}
 
-	return offset, true
+	return tender, err
 }
 
-func getStatus(ctx *gin.Context) (string, bool) {
-	status := ctx.Query("status")
+func (s *Service) checkVisible(ctx context.Context, username string, tender Tender) error {
+	if tender.Status == TenderStatusPublished {
+		return nil
+	}
 
-	if err := validateStatus(status); status == "" || err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid status"})
-		return "", false
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return err
 	}
 
-	return status, true
+	return s.checkPermission(ctx, username, access.PermissionTenderView, tender.OrganizationId.String())
 }
 
-func getVersion(ctx *gin.Context) (int, bool) {
-	version, err := strconv.Atoi(ctx.Param("version"))
+// checkVersionVisible hides the versions from before publication from anyone
+// without tender:view in the organization.
+func (s *Service) checkVersionVisible(ctx context.Context, username string, version Tender) error {
+	if version.Status != TenderStatusCreated {
+		return nil
+	}
+
+	return s.checkVisible(ctx, username, version)
+}
 
-	if err != nil || version < 1 {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Version must be >= 1"})
-		return 0, false
+func (s *Service) canViewDrafts(ctx context.Context, username string, tender Tender) (bool, error) {
+	if username == "" {
+		return false, nil
 	}
 
-	return version, true
+	return s.access.Can(ctx, username, access.PermissionTenderView, tender.OrganizationId.String())
+}
+
+func pageKey(tender Tender) page.Key {
+	return page.Key{Name: tender.Name, Id: tender.Id}
 }
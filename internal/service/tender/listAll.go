@@ -1,59 +1,30 @@
 package tender
 
 import (
-	"database/sql"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
 )
 
-func (s *Service) ListAll(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	limit, ok := getLimit(ctx)
-	if !ok {
-		return
-	}
-
-	offset, ok := getOffset(ctx)
-	if !ok {
-		return
+func (s *Service) ListAll(ctx context.Context, serviceType TenderServiceType, request page.Request) (page.Page[Tender], error) {
+	if err := validatePage(request); err != nil {
+		return page.Page[Tender]{}, err
 	}
 
-	serviceType := ctx.Query("service_type")
-
-	query := "SELECT * FROM tender"
-	paramCounter := 1
-
 	if serviceType != "" {
 		if err := validateServiceType(serviceType); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"reason": "Invalid service type value"})
-			return
+			return page.Page[Tender]{}, err
 		}
-		query += fmt.Sprintf("\nWHERE service_type = $%d AND status = $%d ORDER BY name LIMIT $%d OFFSET $%d", paramCounter, paramCounter+1, paramCounter+2, paramCounter+3)
-		paramCounter += 2
-	} else {
-		query += fmt.Sprintf("\nWHERE status = $%d ORDER BY name ASC LIMIT $%d OFFSET $%d", paramCounter, paramCounter+1, paramCounter+2)
 	}
 
-	var rows *sql.Rows
-	var err error
-
-	if serviceType == "" {
-		rows, err = db.Query(query, TenderStatusPublished, limit, offset)
-	} else {
-		rows, err = db.Query(query, TenderServiceType(serviceType), TenderStatusPublished, limit, offset)
-	}
+	tenders, err := s.repo.ListPublished(ctx, serviceType, request.Lookahead())
 	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+		return page.Page[Tender]{}, err
 	}
-	defer rows.Close()
 
-	tenders, ok := extractTenders(ctx, rows)
-	if !ok {
-		return
+	total, err := s.repo.CountPublished(ctx, serviceType)
+	if err != nil {
+		return page.Page[Tender]{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, tenders)
+	return page.New(tenders, request.Limit, total, pageKey), nil
 }
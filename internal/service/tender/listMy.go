@@ -1,46 +1,31 @@
 package tender
 
 import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
 )
 
-func (s *Service) ListMy(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	limit, ok := getLimit(ctx)
-	if !ok {
-		return
-	}
-
-	offset, ok := getOffset(ctx)
-	if !ok {
-		return
+func (s *Service) ListMy(ctx context.Context, username string, request page.Request) (page.Page[Tender], error) {
+	if err := validatePage(request); err != nil {
+		return page.Page[Tender]{}, err
 	}
 
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return page.Page[Tender]{}, err
 	}
 
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
-	}
-
-	query := "SELECT * FROM tender WHERE creator_username = $1 ORDER BY name LIMIT $2 OFFSET $3"
+	organizationId, _ := access.OrganizationFrom(ctx)
 
-	rows, err := db.Query(query, username, limit, offset)
+	tenders, err := s.repo.ListByCreator(ctx, username, organizationId, request.Lookahead())
 	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+		return page.Page[Tender]{}, err
 	}
-	defer rows.Close()
 
-	tenders, ok := extractTenders(ctx, rows)
-	if !ok {
-		return
+	total, err := s.repo.CountByCreator(ctx, username, organizationId)
+	if err != nil {
+		return page.Page[Tender]{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, tenders)
+	return page.New(tenders, request.Limit, total, pageKey), nil
 }
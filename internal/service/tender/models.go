@@ -1,6 +1,7 @@
 package tender
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"time"
 )
@@ -30,10 +31,45 @@ type Tender struct {
 	OrganizationId  uuid.UUID         `json:"organizationId" binding:"required"`
 	CreatorUsername string            `json:"creatorUsername" binding:"required"`
 	CreatedAt       time.Time         `json:"createdAt"`
+	PublishAt       *time.Time        `json:"publishAt,omitempty"`
+	BidDeadline     *time.Time        `json:"bidDeadline,omitempty"`
+	CloseAt         *time.Time        `json:"closeAt,omitempty"`
 }
 
 type TenderPatch struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description"`
 	ServiceType TenderServiceType `json:"serviceType"`
+	PublishAt   OptionalTime      `json:"publishAt"`
+	BidDeadline OptionalTime      `json:"bidDeadline"`
+	CloseAt     OptionalTime      `json:"closeAt"`
+}
+
+// OptionalTime is a patch field that tells a missing value, which keeps the
+// current one, from an explicit null, which clears it.
+type OptionalTime struct {
+	Set   bool
+	Value *time.Time
+}
+
+func (o *OptionalTime) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type TenderDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
 }
@@ -1,119 +1,87 @@
 package tender
 
 import (
-	"database/sql"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
-	"strings"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"time"
 )
 
-func (s *Service) Patch(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tenderId, ok := getTenderId(ctx)
-	if !ok {
-		return
-	}
-
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
-	}
-
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
-	}
-
-	var tenderPatch TenderPatch
-	if err := ctx.ShouldBindJSON(&tenderPatch); err != nil {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": "Invalid request body"})
-		return
+func (s *Service) Patch(ctx context.Context, tenderId string, username string, tenderPatch TenderPatch, expectedVersion int) (Tender, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return Tender{}, err
 	}
 
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+	if tenderPatch.ServiceType != "" {
+		if err := validateServiceType(tenderPatch.ServiceType); err != nil {
+			return Tender{}, err
+		}
 	}
 
-	tender, ok := getTenderById(tx, ctx, tenderId)
-	if !ok {
-		return
+	tenderPatch.PublishAt.Value, tenderPatch.BidDeadline.Value, tenderPatch.CloseAt.Value = utc(tenderPatch.PublishAt.Value), utc(tenderPatch.BidDeadline.Value), utc(tenderPatch.CloseAt.Value)
+	if err := validateFuture(time.Now().UTC(), tenderPatch.PublishAt.Value, tenderPatch.BidDeadline.Value, tenderPatch.CloseAt.Value); err != nil {
+		return Tender{}, err
 	}
 
-	if username != tender.CreatorUsername {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Tender{}, err
 	}
 
-	changes := make(map[string]interface{})
-	tenderDiffValues := make(map[string]interface{})
+	var updatedTender Tender
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		tender, err := s.getTenderById(ctx, tenderId)
+		if err != nil {
+			return err
+		}
 
-	if tenderPatch.Name != "" {
-		changes["name"] = tenderPatch.Name
-		tenderDiffValues["name"] = tenderPatch.Name
-		tender.Name = tenderPatch.Name
-	}
+		if err = checkExpectedVersion(tender, expectedVersion); err != nil {
+			return err
+		}
 
-	if tenderPatch.Description != "" {
-		changes["description"] = tenderPatch.Description
-		tenderDiffValues["description"] = tenderPatch.Description
-		tender.Description = tenderPatch.Description
-	}
+		if err = s.checkPermission(ctx, username, access.PermissionTenderEdit, tender.OrganizationId.String()); err != nil {
+			return err
+		}
 
-	if tenderPatch.ServiceType != "" {
-		changes["service_type"] = tenderPatch.ServiceType
-		tenderDiffValues["service_type"] = tenderPatch.ServiceType
-		tender.ServiceType = tenderPatch.ServiceType
-	}
+		if tenderPatch.Name != "" {
+			tender.Name = tenderPatch.Name
+		}
 
-	changes["version"] = tender.Version + 1
+		if tenderPatch.Description != "" {
+			tender.Description = tenderPatch.Description
+		}
 
-	var updates []string
-	var params []interface{}
-	paramCounter := 1
+		if tenderPatch.ServiceType != "" {
+			tender.ServiceType = tenderPatch.ServiceType
+		}
 
-	for column, value := range changes {
-		updates = append(updates, fmt.Sprintf("%s = $%d", column, paramCounter))
-		params = append(params, value)
-		paramCounter++
-	}
+		if tenderPatch.PublishAt.Set {
+			tender.PublishAt = tenderPatch.PublishAt.Value
+		}
 
-	queryUpdate := fmt.Sprintf("UPDATE tender SET %s WHERE id = $%d", strings.Join(updates, ", "), paramCounter)
+		if tenderPatch.BidDeadline.Set {
+			tender.BidDeadline = tenderPatch.BidDeadline.Value
+		}
 
-	params = append(params, tenderId)
+		if tenderPatch.CloseAt.Set {
+			tender.CloseAt = tenderPatch.CloseAt.Value
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, params...)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("err: %v, rollbackErr: %v", err, rollbackErr)})
-			return
+		if err = validateScheduleOrder(tender); err != nil {
+			return err
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertTenderDiff(tx, ctx, tender) {
-		return
-	}
+		updatedTender = tender
+		updatedTender.Version = tender.Version + 1
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = s.saveTender(ctx, updatedTender); err != nil {
+			return err
+		}
 
-	returningTender := Tender{
-		Id:              tender.Id,
-		Name:            tender.Name,
-		Description:     tender.Description,
-		Status:          tender.Status,
-		ServiceType:     tender.ServiceType,
-		Version:         tender.Version + 1,
-		OrganizationId:  tender.OrganizationId,
-		CreatorUsername: tender.CreatorUsername,
-		CreatedAt:       tender.CreatedAt,
+		return s.record(ctx, event.TenderUpdated, updatedTender, updatedTender.Status == TenderStatusPublished)
+	})
+	if err != nil {
+		return Tender{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningTender)
+	return updatedTender, nil
 }
@@ -1,87 +1,50 @@
 package tender
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
 )
 
-func (s *Service) PutStatus(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tenderId, ok := getTenderId(ctx)
-	if !ok {
-		return
-	}
-
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
-	}
-
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
-	}
-
-	newStatus, ok := getStatus(ctx)
-	if !ok {
-		return
-	}
-
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
+func (s *Service) PutStatus(ctx context.Context, tenderId string, username string, newStatus TenderStatus, expectedVersion int) (Tender, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return Tender{}, err
 	}
 
-	tender, ok := getTenderById(tx, ctx, tenderId)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Tender{}, err
 	}
 
-	if newStatus == string(tender.Status) {
-		ctx.IndentedJSON(http.StatusBadRequest, gin.H{"reason": fmt.Sprintf("Status is already %v", newStatus)})
-		return
+	if err := validateStatus(newStatus); err != nil {
+		return Tender{}, err
 	}
 
-	if username != tender.CreatorUsername {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return
-	}
-
-	queryUpdate := "UPDATE tender SET status = $1, version = $2 WHERE id = $3"
+	var updatedTender Tender
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		tender, err := s.getTenderById(ctx, tenderId)
+		if err != nil {
+			return err
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, newStatus, tender.Version+1, tenderId)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("err: %v, rollbackErr: %v", err, rollbackErr)})
-			return
+		if err = checkExpectedVersion(tender, expectedVersion); err != nil {
+			return err
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertTenderDiff(tx, ctx, tender) {
-		return
-	}
+		if newStatus == tender.Status {
+			return errs.Validation(fmt.Sprintf("Status is already %v", newStatus))
+		}
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = s.checkPermission(ctx, username, access.PermissionTenderPublish, tender.OrganizationId.String()); err != nil {
+			return err
+		}
 
-	returningTender := Tender{
-		Id:              tender.Id,
-		Name:            tender.Name,
-		Description:     tender.Description,
-		Status:          TenderStatus(newStatus),
-		ServiceType:     tender.ServiceType,
-		Version:         tender.Version + 1,
-		OrganizationId:  tender.OrganizationId,
-		CreatorUsername: tender.CreatorUsername,
-		CreatedAt:       tender.CreatedAt,
+		updatedTender, err = s.Transition(ctx, tender, newStatus, ActorResponsible)
+		return err
+	})
+	if err != nil {
+		return Tender{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningTender)
+	return updatedTender, nil
 }
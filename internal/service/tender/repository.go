@@ -0,0 +1,26 @@
+package tender
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"time"
+)
+
+type TenderRepository interface {
+	repository.Transactor
+	repository.Locker
+	Create(ctx context.Context, tender Tender) (Tender, error)
+	Get(ctx context.Context, tenderId string) (Tender, error)
+	GetVersion(ctx context.Context, tenderId string, version int) (Tender, error)
+	ListVersions(ctx context.Context, tenderId string, withDrafts bool, limit int, offset int) ([]Tender, error)
+	Update(ctx context.Context, tender Tender) error
+	InsertDiff(ctx context.Context, tender Tender) error
+	// ListPublished and ListByCreator order tenders by name and id. An empty
+	// organizationId lists the creator's tenders in every organization.
+	ListPublished(ctx context.Context, serviceType TenderServiceType, request page.Request) ([]Tender, error)
+	CountPublished(ctx context.Context, serviceType TenderServiceType) (int, error)
+	ListByCreator(ctx context.Context, username string, organizationId string, request page.Request) ([]Tender, error)
+	CountByCreator(ctx context.Context, username string, organizationId string) (int, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]Tender, error)
+}
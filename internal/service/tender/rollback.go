@@ -1,78 +1,60 @@
 package tender
 
 import (
-	"database/sql"
-	"fmt"
-	"github.com/gin-gonic/gin"
-	"net/http"
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
 )
 
-func (s *Service) Rollback(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tenderId, ok := getTenderId(ctx)
-	if !ok {
-		return
+func (s *Service) Rollback(ctx context.Context, tenderId string, username string, version int, expectedVersion int) (Tender, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return Tender{}, err
 	}
 
-	newVersion, ok := getVersion(ctx)
-	if !ok {
-		return
+	if err := validateVersion(version); err != nil {
+		return Tender{}, err
 	}
 
-	username, ok := getUsername(ctx)
-	if !ok {
-		return
+	if err := s.checkUserExistence(ctx, username); err != nil {
+		return Tender{}, err
 	}
 
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
-	if err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+	var updatedTender Tender
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		tender, err := s.getTenderById(ctx, tenderId)
+		if err != nil {
+			return err
+		}
 
-	currentVersion, ok := checkVersionAndUsername(tx, ctx, newVersion, username, tenderId)
-	if !ok {
-		return
-	}
+		if err = checkExpectedVersion(tender, expectedVersion); err != nil {
+			return err
+		}
 
-	newTender, ok := getTenderByIdAndVersion(tx, ctx, tenderId, newVersion)
-	if !ok {
-		return
-	}
+		if err = checkVersion(tender, version); err != nil {
+			return err
+		}
 
-	queryUpdate := "UPDATE tender SET name = $1, description = $2, status = $3, service_type = $4, version = $5, organization_id = $6, creator_username = $7, created_at = $8 WHERE id = $9"
+		if err = s.checkPermission(ctx, username, access.PermissionTenderEdit, tender.OrganizationId.String()); err != nil {
+			return err
+		}
 
-	_, err = tx.ExecContext(ctx, queryUpdate, newTender.Name, newTender.Description, newTender.Status, newTender.ServiceType, currentVersion+1, newTender.OrganizationId, newTender.CreatorUsername, newTender.CreatedAt, newTender.Id)
-	if err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": fmt.Sprintf("Failed to rollback: %v", rollbackErr)})
-			return
+		newTender, err := s.getTenderByIdAndVersion(ctx, tenderId, version)
+		if err != nil {
+			return err
 		}
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
 
-	if !insertTenderDiff(tx, ctx, newTender) {
-		return
-	}
+		updatedTender = newTender
+		updatedTender.Version = tender.Version + 1
 
-	if err = tx.Commit(); err != nil {
-		ctx.IndentedJSON(http.StatusInternalServerError, gin.H{"reason": err.Error()})
-		return
-	}
+		if err = s.saveTransition(ctx, tender.Status, updatedTender, ActorResponsible); err != nil {
+			return err
+		}
 
-	returningTender := Tender{
-		Id:              newTender.Id,
-		Name:            newTender.Name,
-		Description:     newTender.Description,
-		Status:          newTender.Status,
-		ServiceType:     newTender.ServiceType,
-		Version:         currentVersion + 1,
-		OrganizationId:  newTender.OrganizationId,
-		CreatorUsername: newTender.CreatorUsername,
-		CreatedAt:       newTender.CreatedAt,
+		return s.record(ctx, event.TenderRolledBack, updatedTender, updatedTender.Status == TenderStatusPublished)
+	})
+	if err != nil {
+		return Tender{}, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, returningTender)
+	return updatedTender, nil
 }
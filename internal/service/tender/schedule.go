@@ -0,0 +1,96 @@
+package tender
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const scheduleBatchSize = 100
+
+// ApplySchedule publishes and closes the tenders whose publishAt or closeAt
+// has passed by now. Each tender is handled in its own transaction under an
+// advisory lock, so concurrent schedulers never move the same tender twice.
+func (s *Service) ApplySchedule(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.repo.ListDue(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, t := range due {
+		moved, err := s.applyTenderSchedule(ctx, t.Id.String(), now)
+		if err != nil {
+			log.Printf("Error applying schedule to tender %v: %v", t.Id, err)
+			continue
+		}
+
+		if moved {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+func (s *Service) applyTenderSchedule(ctx context.Context, tenderId string, now time.Time) (bool, error) {
+	moved := false
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		locked, err := s.repo.TryLock(ctx, "tender:"+tenderId)
+		if err != nil || !locked {
+			return err
+		}
+
+		tender, err := s.getTenderById(ctx, tenderId)
+		if err != nil {
+			return err
+		}
+
+		status, ok := scheduledStatus(tender, now)
+		if !ok {
+			return nil
+		}
+
+		if _, err = s.Transition(ctx, tender, status, ActorSystem); err != nil {
+			return err
+		}
+
+		moved = true
+		return nil
+	})
+
+	return moved, err
+}
+
+func scheduledStatus(tender Tender, now time.Time) (TenderStatus, bool) {
+	if tender.Status == TenderStatusClosed {
+		return "", false
+	}
+
+	if tender.CloseAt != nil && !tender.CloseAt.After(now) {
+		return TenderStatusClosed, true
+	}
+
+	if tender.Status == TenderStatusCreated && tender.PublishAt != nil && !tender.PublishAt.After(now) {
+		return TenderStatusPublished, true
+	}
+
+	return "", false
+}
+
+// RunScheduler applies the schedule every interval until ctx is cancelled.
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ApplySchedule(ctx, time.Now().UTC()); err != nil {
+				log.Println("Error applying tender schedule:", err)
+			}
+		}
+	}
+}
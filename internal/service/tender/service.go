@@ -1,7 +1,22 @@
 package tender
 
-type Service struct{}
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+)
 
-func NewService() *Service {
-	return &Service{}
+type Service struct {
+	repo    TenderRepository
+	access  *access.Service
+	events  event.Outbox
+	effects map[TenderStatus][]Effect
+}
+
+func NewService(repo TenderRepository, access *access.Service, events event.Outbox) *Service {
+	return &Service{
+		repo:    repo,
+		access:  access,
+		events:  events,
+		effects: make(map[TenderStatus][]Effect),
+	}
 }
@@ -0,0 +1,555 @@
+package tender_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/idempotency"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/page"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/tender"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixture struct {
+	router         *gin.Engine
+	service        *tender.Service
+	organizationId string
+}
+
+func newFixture() fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	store.AddResponsible(organizationId, store.AddEmployee("owner"))
+	store.AddResponsible(organizationId, store.AddEmployee("colleague"))
+	store.AddMember(organizationId, store.AddEmployee("evaluator"), access.RoleEvaluator)
+	store.AddMember(organizationId, store.AddEmployee("viewer"), access.RoleViewer)
+	store.AddEmployee("stranger")
+
+	service := tender.NewService(memory.NewTenderRepository(store), access.NewService(memory.NewAccessRepository(store)), memory.NewOutboxRepository(store))
+	cmd := commands.NewCommander(service, nil, idempotency.NewService(memory.NewIdempotencyRepository(store), time.Hour), nil, nil, nil, nil, nil, page.NewCodec([]byte("test-secret")))
+
+	router := gin.New()
+	router.GET("/tenders", cmd.ListAllTenders)
+	router.GET("/tenders/my", cmd.ListMyTenders)
+	router.POST("/tenders/new", cmd.AddTender)
+	router.GET("/tenders/:tenderId", cmd.GetTender)
+	router.GET("/tenders/:tenderId/status", cmd.TenderStatus)
+	router.PUT("/tenders/:tenderId/status", cmd.PutTenderStatus)
+	router.PATCH("/tenders/:tenderId/edit", cmd.PatchTender)
+	router.PUT("/tenders/:tenderId/rollback/:version", cmd.TenderRollback)
+	router.GET("/tenders/:tenderId/versions", cmd.TenderVersions)
+	router.GET("/tenders/:tenderId/versions/:version", cmd.TenderVersion)
+	router.GET("/tenders/:tenderId/diff", cmd.TenderDiff)
+
+	return fixture{router: router, service: service, organizationId: organizationId}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(method, target, &payload))
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func (f fixture) add(t *testing.T, creator string) (tender.Tender, int) {
+	t.Helper()
+
+	var created tender.Tender
+	code := f.do(t, http.MethodPost, "/tenders/new", gin.H{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     tender.TenderServiceTypeDelivery,
+		"organizationId":  f.organizationId,
+		"creatorUsername": creator,
+	}, &created)
+
+	return created, code
+}
+
+func TestAddRequiresResponsible(t *testing.T) {
+	f := newFixture()
+
+	if _, code := f.add(t, "stranger"); code != http.StatusForbidden {
+		t.Fatalf("stranger add code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if _, code := f.add(t, "nobody"); code != http.StatusUnauthorized {
+		t.Fatalf("unknown user add code = %d, want %d", code, http.StatusUnauthorized)
+	}
+
+	created, code := f.add(t, "owner")
+	if code != http.StatusCreated {
+		t.Fatalf("owner add code = %d, want %d", code, http.StatusCreated)
+	}
+	if created.Status != tender.TenderStatusCreated || created.Version != 1 {
+		t.Fatalf("created tender = %+v", created)
+	}
+}
+
+func TestAnyResponsibleManagesTender(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	var published tender.Tender
+	if code := f.do(t, http.MethodPut, target+"/status?status=Published&username=colleague", nil, &published); code != http.StatusOK {
+		t.Fatalf("publish code = %d, want %d", code, http.StatusOK)
+	}
+	if published.Status != tender.TenderStatusPublished || published.Version != 2 {
+		t.Fatalf("published tender = %+v", published)
+	}
+
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=stranger", gin.H{"name": "Hijacked"}, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger patch code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	var listed []tender.Tender
+	if code := f.do(t, http.MethodGet, "/tenders", nil, &listed); code != http.StatusOK {
+		t.Fatalf("list code = %d, want %d", code, http.StatusOK)
+	}
+	if len(listed) != 1 || listed[0].Id != created.Id {
+		t.Fatalf("listed = %+v", listed)
+	}
+}
+
+func TestRolesLimitTenderActions(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	for _, username := range []string{"evaluator", "viewer"} {
+		if _, code := f.add(t, username); code != http.StatusForbidden {
+			t.Fatalf("%s add code = %d, want %d", username, code, http.StatusForbidden)
+		}
+		if code := f.do(t, http.MethodPatch, target+"/edit?username="+username, gin.H{"name": "Renamed"}, nil); code != http.StatusForbidden {
+			t.Fatalf("%s patch code = %d, want %d", username, code, http.StatusForbidden)
+		}
+		if code := f.do(t, http.MethodPut, target+"/status?status=Published&username="+username, nil, nil); code != http.StatusForbidden {
+			t.Fatalf("%s publish code = %d, want %d", username, code, http.StatusForbidden)
+		}
+		if code := f.do(t, http.MethodGet, target+"/status?username="+username, nil, nil); code != http.StatusOK {
+			t.Fatalf("%s status code = %d, want %d", username, code, http.StatusOK)
+		}
+	}
+}
+
+func TestGetFollowsVisibility(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	var found tender.Tender
+	if code := f.do(t, http.MethodGet, target+"?username=viewer", nil, &found); code != http.StatusOK {
+		t.Fatalf("member get code = %d, want %d", code, http.StatusOK)
+	}
+	if found.Id != created.Id || found.Version != 1 {
+		t.Fatalf("found tender = %+v", found)
+	}
+
+	if code := f.do(t, http.MethodGet, target+"?username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger get code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodGet, target, nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("anonymous get code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.do(t, http.MethodGet, "/tenders/"+uuid.NewString()+"?username=owner", nil, nil); code != http.StatusNotFound {
+		t.Fatalf("unknown get code = %d, want %d", code, http.StatusNotFound)
+	}
+
+	f.do(t, http.MethodPut, target+"/status?status=Published&username=owner", nil, nil)
+	if code := f.do(t, http.MethodGet, target, nil, nil); code != http.StatusOK {
+		t.Fatalf("anonymous get published code = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestCursorWalksPages(t *testing.T) {
+	f := newFixture()
+	for range 3 {
+		f.add(t, "owner")
+	}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		f.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+		return recorder
+	}
+
+	first := get("/tenders/my?username=owner&limit=2")
+	if first.Code != http.StatusOK || first.Header().Get("X-Total-Count") != "3" {
+		t.Fatalf("first page code = %d, total = %q", first.Code, first.Header().Get("X-Total-Count"))
+	}
+
+	empty := get("/tenders/my?username=owner&limit=0")
+	if empty.Code != http.StatusOK || strings.TrimSpace(empty.Body.String()) != "[]" || empty.Header().Get("X-Total-Count") != "3" {
+		t.Fatalf("zero limit code = %d, body = %s, total = %q", empty.Code, empty.Body.String(), empty.Header().Get("X-Total-Count"))
+	}
+
+	cursor := first.Header().Get("X-Next-Cursor")
+	link := first.Header().Get("Link")
+	if cursor == "" || !strings.Contains(link, "cursor="+cursor) {
+		t.Fatalf("cursor = %q, link = %q", cursor, link)
+	}
+
+	next := get(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+	var firstPage, nextPage []tender.Tender
+	_ = json.Unmarshal(first.Body.Bytes(), &firstPage)
+	_ = json.Unmarshal(next.Body.Bytes(), &nextPage)
+	if next.Code != http.StatusOK || len(nextPage) != 1 || next.Header().Get("X-Total-Count") != "3" {
+		t.Fatalf("next page code = %d, %+v", next.Code, nextPage)
+	}
+	if nextPage[0].Id.String() <= firstPage[1].Id.String() {
+		t.Fatalf("next page %+v doesn't continue after %+v", nextPage, firstPage)
+	}
+	if next.Header().Get("X-Next-Cursor") != "" {
+		t.Fatalf("last page has next cursor %q", next.Header().Get("X-Next-Cursor"))
+	}
+
+	if code := get("/tenders/my?username=owner&cursor=" + cursor + "x").Code; code != http.StatusBadRequest {
+		t.Fatalf("tampered cursor code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := get("/tenders/my?username=owner&offset=1&cursor=" + cursor).Code; code != http.StatusBadRequest {
+		t.Fatalf("offset with cursor code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := get("/tenders/my?username=owner&offset=0&cursor=" + cursor).Code; code != http.StatusOK {
+		t.Fatalf("zero offset with cursor code = %d, want %d", code, http.StatusOK)
+	}
+	if code := get("/tenders/my?username=colleague&cursor=" + cursor).Code; code != http.StatusBadRequest {
+		t.Fatalf("cursor of another user code = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := get("/tenders?username=owner&cursor=" + cursor).Code; code != http.StatusBadRequest {
+		t.Fatalf("cursor of another list code = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestRollbackRestoresSnapshot(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=owner", gin.H{"name": "Second"}, nil); code != http.StatusOK {
+		t.Fatalf("patch code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=owner", gin.H{"name": "Third"}, nil); code != http.StatusOK {
+		t.Fatalf("patch code = %d, want %d", code, http.StatusOK)
+	}
+
+	for version, name := range map[int]string{1: "Delivery", 2: "Second"} {
+		var restored tender.Tender
+		if code := f.do(t, http.MethodPut, target+"/rollback/"+strconv.Itoa(version)+"?username=owner", nil, &restored); code != http.StatusOK {
+			t.Fatalf("rollback to %d code = %d, want %d", version, code, http.StatusOK)
+		}
+		if restored.Name != name {
+			t.Fatalf("rollback to %d name = %q, want %q", version, restored.Name, name)
+		}
+	}
+}
+
+func TestVersionsHistory(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=owner", gin.H{"name": "Second"}, nil); code != http.StatusOK {
+		t.Fatalf("patch code = %d, want %d", code, http.StatusOK)
+	}
+
+	if code := f.do(t, http.MethodGet, target+"/versions?username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger versions code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	var versions []tender.Tender
+	if code := f.do(t, http.MethodGet, target+"/versions?username=colleague", nil, &versions); code != http.StatusOK {
+		t.Fatalf("versions code = %d, want %d", code, http.StatusOK)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[0].Name != "Second" || versions[1].Name != "Delivery" {
+		t.Fatalf("versions = %+v", versions)
+	}
+
+	var first tender.Tender
+	if code := f.do(t, http.MethodGet, target+"/versions/1?username=owner", nil, &first); code != http.StatusOK {
+		t.Fatalf("version code = %d, want %d", code, http.StatusOK)
+	}
+	if first.Version != 1 || first.Name != "Delivery" {
+		t.Fatalf("version 1 = %+v", first)
+	}
+
+	if code := f.do(t, http.MethodGet, target+"/versions/3?username=owner", nil, nil); code != http.StatusNotFound {
+		t.Fatalf("missing version code = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestDiffBetweenVersions(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=owner", gin.H{"name": "Second"}, nil); code != http.StatusOK {
+		t.Fatalf("patch code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodPut, target+"/status?status=Published&username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("publish code = %d, want %d", code, http.StatusOK)
+	}
+
+	var diff tender.TenderDiff
+	if code := f.do(t, http.MethodGet, target+"/diff?from=1&to=3&username=owner", nil, &diff); code != http.StatusOK {
+		t.Fatalf("diff code = %d, want %d", code, http.StatusOK)
+	}
+
+	want := []tender.FieldChange{
+		{Field: "name", From: "Delivery", To: "Second"},
+		{Field: "status", From: string(tender.TenderStatusCreated), To: string(tender.TenderStatusPublished)},
+	}
+	if !slices.Equal(diff.Changes, want) {
+		t.Fatalf("changes = %+v, want %+v", diff.Changes, want)
+	}
+
+	if code := f.do(t, http.MethodGet, target+"/diff?from=1&to=4&username=owner", nil, nil); code != http.StatusNotFound {
+		t.Fatalf("missing version diff code = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestDraftVersionsStayWithOrganization(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String()
+
+	if code := f.do(t, http.MethodPatch, target+"/edit?username=owner", gin.H{"name": "Second"}, nil); code != http.StatusOK {
+		t.Fatalf("patch code = %d, want %d", code, http.StatusOK)
+	}
+	if code := f.do(t, http.MethodPut, target+"/status?status=Published&username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("publish code = %d, want %d", code, http.StatusOK)
+	}
+
+	for username, want := range map[string]int{"": 1, "stranger": 1, "colleague": 3} {
+		var versions []tender.Tender
+		if code := f.do(t, http.MethodGet, target+"/versions?username="+username, nil, &versions); code != http.StatusOK {
+			t.Fatalf("%q versions code = %d, want %d", username, code, http.StatusOK)
+		}
+		if len(versions) != want {
+			t.Fatalf("%q sees %d versions, want %d", username, len(versions), want)
+		}
+	}
+
+	if code := f.do(t, http.MethodGet, target+"/versions/1", nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("anonymous draft version code = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := f.do(t, http.MethodGet, target+"/versions/2?username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger draft version code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodGet, target+"/diff?from=1&to=3&username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger draft diff code = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := f.do(t, http.MethodGet, target+"/diff?from=1&to=3&username=colleague", nil, nil); code != http.StatusOK {
+		t.Fatalf("colleague draft diff code = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestIfMatchPrecondition(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String() + "/edit?username=owner"
+
+	patch := func(ifMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPatch, target, bytes.NewBufferString(`{"name": "Renamed"}`))
+		request.Header.Set("If-Match", ifMatch)
+
+		recorder := httptest.NewRecorder()
+		f.router.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	first := patch(`"1"`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first patch code = %d, want %d", first.Code, http.StatusOK)
+	}
+	if etag := first.Header().Get("ETag"); etag != `"2"` {
+		t.Fatalf("etag = %s, want %s", etag, `"2"`)
+	}
+
+	if stale := patch(`"1"`); stale.Code != http.StatusPreconditionFailed {
+		t.Fatalf("stale patch code = %d, want %d", stale.Code, http.StatusPreconditionFailed)
+	}
+	if malformed := patch("2"); malformed.Code != http.StatusBadRequest {
+		t.Fatalf("malformed if-match code = %d, want %d", malformed.Code, http.StatusBadRequest)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/tenders/"+created.Id.String()+"/status?username=owner", nil))
+	if etag := recorder.Header().Get("ETag"); recorder.Code != http.StatusOK || etag != `"2"` {
+		t.Fatalf("status code = %d, etag = %s, want %d and %s", recorder.Code, etag, http.StatusOK, `"2"`)
+	}
+}
+
+func TestIdempotencyKeyReplaysCreate(t *testing.T) {
+	f := newFixture()
+
+	post := func(name string) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(gin.H{
+			"name":            name,
+			"description":     "Deliver equipment",
+			"serviceType":     tender.TenderServiceTypeDelivery,
+			"organizationId":  f.organizationId,
+			"creatorUsername": "owner",
+		})
+
+		request := httptest.NewRequest(http.MethodPost, "/tenders/new", &payload)
+		request.Header.Set("Idempotency-Key", "create-delivery")
+
+		recorder := httptest.NewRecorder()
+		f.router.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	first := post("Delivery")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first create code = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	retry := post("Delivery")
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry = %d %s, want replay of %s", retry.Code, retry.Body.String(), first.Body.String())
+	}
+	if retry.Header().Get("ETag") != first.Header().Get("ETag") {
+		t.Fatalf("retry etag = %s, want %s", retry.Header().Get("ETag"), first.Header().Get("ETag"))
+	}
+
+	if changed := post("Construction"); changed.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("changed payload code = %d, want %d", changed.Code, http.StatusUnprocessableEntity)
+	}
+
+	var listed []tender.Tender
+	f.do(t, http.MethodGet, "/tenders/my?username=owner", nil, &listed)
+	if len(listed) != 1 {
+		t.Fatalf("created %d tenders, want 1", len(listed))
+	}
+}
+
+func TestClosedTenderCannotBeReopened(t *testing.T) {
+	f := newFixture()
+	created, _ := f.add(t, "owner")
+	target := "/tenders/" + created.Id.String() + "/status?username=owner&status="
+
+	if code := f.do(t, http.MethodPut, target+string(tender.TenderStatusClosed), nil, nil); code != http.StatusOK {
+		t.Fatalf("close code = %d, want %d", code, http.StatusOK)
+	}
+
+	if code := f.do(t, http.MethodPut, target+string(tender.TenderStatusCreated), nil, nil); code != http.StatusBadRequest {
+		t.Fatalf("reopen code = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	if code := f.do(t, http.MethodPut, "/tenders/"+created.Id.String()+"/rollback/1?username=owner", nil, nil); code != http.StatusBadRequest {
+		t.Fatalf("rollback to created code = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestSchedulerPublishesAndClosesTender(t *testing.T) {
+	f := newFixture()
+	now := time.Now().UTC()
+	body := gin.H{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     tender.TenderServiceTypeDelivery,
+		"organizationId":  f.organizationId,
+		"creatorUsername": "owner",
+		"publishAt":       now.Add(2 * time.Hour),
+		"closeAt":         now.Add(time.Hour),
+	}
+
+	if code := f.do(t, http.MethodPost, "/tenders/new", body, nil); code != http.StatusBadRequest {
+		t.Fatalf("close before publish code = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	body["closeAt"] = now.Add(3 * time.Hour)
+	var created tender.Tender
+	if code := f.do(t, http.MethodPost, "/tenders/new", body, &created); code != http.StatusCreated {
+		t.Fatalf("add code = %d, want %d", code, http.StatusCreated)
+	}
+
+	if applied, err := f.service.ApplySchedule(context.Background(), now.Add(time.Hour)); err != nil || applied != 0 {
+		t.Fatalf("early apply = %d, %v", applied, err)
+	}
+
+	if applied, err := f.service.ApplySchedule(context.Background(), now.Add(150*time.Minute)); err != nil || applied != 1 {
+		t.Fatalf("publish apply = %d, %v", applied, err)
+	}
+
+	if applied, err := f.service.ApplySchedule(context.Background(), now.Add(4*time.Hour)); err != nil || applied != 1 {
+		t.Fatalf("close apply = %d, %v", applied, err)
+	}
+
+	var versions []tender.Tender
+	if code := f.do(t, http.MethodGet, "/tenders/"+created.Id.String()+"/versions?username=owner", nil, &versions); code != http.StatusOK {
+		t.Fatalf("versions code = %d, want %d", code, http.StatusOK)
+	}
+	if len(versions) != 3 || versions[0].Status != tender.TenderStatusClosed || versions[1].Status != tender.TenderStatusPublished {
+		t.Fatalf("versions = %+v", versions)
+	}
+}
+
+func TestPatchClearsSchedule(t *testing.T) {
+	f := newFixture()
+	now := time.Now().UTC()
+
+	var created tender.Tender
+	code := f.do(t, http.MethodPost, "/tenders/new", gin.H{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     tender.TenderServiceTypeDelivery,
+		"organizationId":  f.organizationId,
+		"creatorUsername": "owner",
+		"publishAt":       now.Add(time.Hour),
+		"closeAt":         now.Add(2 * time.Hour),
+	}, &created)
+	if code != http.StatusCreated {
+		t.Fatalf("add code = %d, want %d", code, http.StatusCreated)
+	}
+
+	target := "/tenders/" + created.Id.String() + "/edit?username=owner"
+	var renamed tender.Tender
+	if code = f.do(t, http.MethodPatch, target, gin.H{"name": "Renamed"}, &renamed); code != http.StatusOK {
+		t.Fatalf("rename code = %d, want %d", code, http.StatusOK)
+	}
+	if renamed.PublishAt == nil || renamed.CloseAt == nil {
+		t.Fatalf("rename changed the schedule: %+v", renamed)
+	}
+
+	var cleared tender.Tender
+	if code = f.do(t, http.MethodPatch, target, gin.H{"closeAt": nil}, &cleared); code != http.StatusOK {
+		t.Fatalf("clear code = %d, want %d", code, http.StatusOK)
+	}
+	if cleared.CloseAt != nil || cleared.PublishAt == nil {
+		t.Fatalf("clear closeAt: publishAt = %v, closeAt = %v", cleared.PublishAt, cleared.CloseAt)
+	}
+}
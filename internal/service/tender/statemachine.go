@@ -0,0 +1,105 @@
+package tender
+
+import (
+	"context"
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"slices"
+)
+
+type Actor string
+
+const (
+	ActorResponsible Actor = "Responsible"
+	ActorSystem      Actor = "System"
+)
+
+// Effect runs inside the transaction that moved tender into a new status.
+type Effect func(ctx context.Context, tender Tender) error
+
+type transition struct {
+	from   TenderStatus
+	to     TenderStatus
+	actors []Actor
+}
+
+var transitions = []transition{
+	{from: TenderStatusCreated, to: TenderStatusPublished, actors: []Actor{ActorResponsible, ActorSystem}},
+	{from: TenderStatusCreated, to: TenderStatusClosed, actors: []Actor{ActorResponsible, ActorSystem}},
+	{from: TenderStatusPublished, to: TenderStatusClosed, actors: []Actor{ActorResponsible, ActorSystem}},
+}
+
+var statusEvents = map[TenderStatus]event.Type{
+	TenderStatusPublished: event.TenderPublished,
+	TenderStatusClosed:    event.TenderClosed,
+}
+
+// AllowedTransitions lists the statuses actor may move a tender in status from to.
+func AllowedTransitions(from TenderStatus, actor Actor) []TenderStatus {
+	allowed := []TenderStatus{}
+	for _, t := range transitions {
+		if t.from == from && slices.Contains(t.actors, actor) {
+			allowed = append(allowed, t.to)
+		}
+	}
+
+	return allowed
+}
+
+func checkTransition(from TenderStatus, to TenderStatus, actor Actor) error {
+	allowed := AllowedTransitions(from, actor)
+	if !slices.Contains(allowed, to) {
+		return errs.InvalidTransition(fmt.Sprintf("Tender can't be moved from %v to %v", from, to), allowed)
+	}
+
+	return nil
+}
+
+// OnStatus registers effect to run whenever a tender enters status.
+func (s *Service) OnStatus(status TenderStatus, effect Effect) {
+	s.effects[status] = append(s.effects[status], effect)
+}
+
+// Transition moves tender into status on behalf of actor, records the new
+// version and runs the registered effects. It must be called inside a
+// transaction.
+func (s *Service) Transition(ctx context.Context, tender Tender, status TenderStatus, actor Actor) (Tender, error) {
+	updatedTender := tender
+	updatedTender.Status = status
+	updatedTender.Version++
+
+	if err := s.saveTransition(ctx, tender.Status, updatedTender, actor); err != nil {
+		return Tender{}, err
+	}
+
+	return updatedTender, nil
+}
+
+// saveTransition stores tender as its new version, enforcing the state machine
+// when its status differs from the previous one.
+func (s *Service) saveTransition(ctx context.Context, from TenderStatus, tender Tender, actor Actor) error {
+	if tender.Status == from {
+		return s.saveTender(ctx, tender)
+	}
+
+	if err := checkTransition(from, tender.Status, actor); err != nil {
+		return err
+	}
+
+	if err := s.saveTender(ctx, tender); err != nil {
+		return err
+	}
+
+	if err := s.record(ctx, statusEvents[tender.Status], tender, from == TenderStatusPublished || tender.Status == TenderStatusPublished); err != nil {
+		return err
+	}
+
+	for _, effect := range s.effects[tender.Status] {
+		if err := effect(ctx, tender); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,44 +1,22 @@
 package tender
 
-import (
-	"database/sql"
-	"github.com/gin-gonic/gin"
-	"net/http"
-)
+import "context"
 
-func (s *Service) Status(db *sql.DB, ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
-	tenderId, ok := getTenderId(ctx)
-	if !ok {
-		return
+// Status also returns the current version of the tender, which identifies the
+// answer for caching and conditional updates.
+func (s *Service) Status(ctx context.Context, tenderId string, username string) (TenderStatus, int, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return "", 0, err
 	}
 
-	username := ctx.Query("username")
-
-	query := "SELECT status, creator_username FROM tender WHERE id = $1"
-
-	var status string
-	var creatorUsername string
-
-	err := db.QueryRow(query, tenderId).Scan(&status, &creatorUsername)
+	tender, err := s.getTenderById(ctx, tenderId)
 	if err != nil {
-		ctx.IndentedJSON(http.StatusNotFound, gin.H{"reason": "Tender not found"})
-		return
-	}
-	if status == string(TenderStatusPublished) {
-		ctx.IndentedJSON(http.StatusOK, status)
-		return
-	}
-
-	if userExists := checkUserExistence(db, ctx, username); !userExists {
-		return
+		return "", 0, err
 	}
 
-	if username != creatorUsername {
-		ctx.IndentedJSON(http.StatusForbidden, gin.H{"reason": "Wrong username"})
-		return
+	if err = s.checkVisible(ctx, username, tender); err != nil {
+		return "", 0, err
 	}
 
-	ctx.IndentedJSON(http.StatusOK, status)
+	return tender.Status, tender.Version, nil
 }
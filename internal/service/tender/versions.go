@@ -0,0 +1,67 @@
+package tender
+
+import "context"
+
+// Versions also returns the current version of the tender, which changes
+// whenever the history does. Versions from before publication are left out
+// for callers outside the organization.
+func (s *Service) Versions(ctx context.Context, tenderId string, username string, limit int, offset int) ([]Tender, int, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return nil, 0, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, 0, err
+	}
+
+	tender, err := s.getTenderById(ctx, tenderId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = s.checkVisible(ctx, username, tender); err != nil {
+		return nil, 0, err
+	}
+
+	withDrafts, err := s.canViewDrafts(ctx, username, tender)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	versions, err := s.repo.ListVersions(ctx, tenderId, withDrafts, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return versions, tender.Version, nil
+}
+
+func (s *Service) Version(ctx context.Context, tenderId string, username string, version int) (Tender, error) {
+	if err := validateTenderId(tenderId); err != nil {
+		return Tender{}, err
+	}
+
+	if err := validateVersion(version); err != nil {
+		return Tender{}, err
+	}
+
+	tender, err := s.getTenderById(ctx, tenderId)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if err = s.checkVisible(ctx, username, tender); err != nil {
+		return Tender{}, err
+	}
+
+	versioned, err := s.getTenderByIdAndVersion(ctx, tenderId, version)
+	if err != nil {
+		return Tender{}, err
+	}
+
+	if err = s.checkVersionVisible(ctx, username, versioned); err != nil {
+		return Tender{}, err
+	}
+
+	return versioned, nil
+}
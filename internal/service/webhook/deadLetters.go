@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"time"
+)
+
+func (s *Service) DeadLetters(ctx context.Context, organizationId string, username string, limit int, offset int) ([]Delivery, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return nil, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeadLetters(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Delivery{}, deliveries...), nil
+}
+
+// Redeliver puts a dead letter back into the queue with a fresh attempt budget.
+func (s *Service) Redeliver(ctx context.Context, organizationId string, deliveryId string, username string) (Delivery, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Delivery{}, err
+	}
+
+	if err := validateId(deliveryId, "deliveryId"); err != nil {
+		return Delivery{}, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return Delivery{}, err
+	}
+
+	var requeued Delivery
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		delivery, err := s.repo.GetDeadLetter(ctx, organizationId, deliveryId)
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.NotFound("Dead letter not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = &now
+		delivery.FailedAt = nil
+		requeued = delivery
+
+		return s.repo.Requeue(ctx, delivery)
+	})
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return requeued, nil
+}
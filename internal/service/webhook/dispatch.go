@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	dispatchBatchSize = 20
+	// deliveryLease keeps a claimed delivery from other dispatchers while it is
+	// sent. It must outlast a whole batch of client timeouts.
+	deliveryLease = 5 * time.Minute
+)
+
+// Enqueue schedules delivery of e to every matching subscription of the
+// organization that owns the tender. It is an event.Handler meant for the
+// relay's recorder, so it runs in the transaction that marks e published.
+func (s *Service) Enqueue(ctx context.Context, e event.Event) error {
+	if !event.OwnerVisible(e.Type) {
+		return nil
+	}
+
+	subscriptions, err := s.repo.ListSubscribers(ctx, e.OrganizationId.String(), e.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, subscription := range subscriptions {
+		err = s.repo.EnqueueDelivery(ctx, Delivery{
+			SubscriptionId: subscription.Id,
+			EventId:        e.Id,
+			EventType:      e.Type,
+			Payload:        payload,
+			NextAttemptAt:  &now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dispatch sends one batch of deliveries due by now and reports how many
+// succeeded. Failed deliveries are retried with exponential backoff and moved
+// to the dead letters once they run out of attempts.
+//
+// The batch is claimed with a lease in a short transaction, sent outside of
+// any transaction and each result is recorded in its own one, so a slow
+// receiver holds no locks and a dispatcher that stops leaves its claimed
+// deliveries to be retried when the lease runs out.
+func (s *Service) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	var deliveries []Delivery
+
+	err := repository.InTx(ctx, s.repo, func(ctx context.Context) error {
+		var err error
+		deliveries, err = s.repo.ClaimDueDeliveries(ctx, now, now.Add(deliveryLease), dispatchBatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	subscriptions := make(map[string]Subscription)
+	for _, delivery := range deliveries {
+		subscriptionId := delivery.SubscriptionId.String()
+
+		// A delivery whose subscription can't be loaded counts as a failed
+		// attempt, so the rest of the batch is still sent.
+		var sendErr error
+		subscription, ok := subscriptions[subscriptionId]
+		if !ok {
+			subscription, sendErr = s.repo.GetSubscription(ctx, subscriptionId)
+			if ok = sendErr == nil; ok {
+				subscriptions[subscriptionId] = subscription
+			}
+		}
+
+		if ok {
+			sendErr = s.send(ctx, subscription, delivery)
+		}
+
+		err = repository.InTx(ctx, s.repo, func(ctx context.Context) error {
+			return s.record(ctx, delivery, sendErr, now)
+		})
+		if errors.Is(err, repository.ErrNotFound) {
+			// The delivery was deleted along with its subscription.
+			continue
+		}
+		if err != nil {
+			return delivered, err
+		}
+
+		if sendErr == nil {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+// record stores the outcome of sending delivery once.
+func (s *Service) record(ctx context.Context, delivery Delivery, sendErr error, now time.Time) error {
+	if sendErr == nil {
+		return s.repo.MarkDelivered(ctx, delivery.Id.String(), now)
+	}
+
+	delivery.Attempts++
+	delivery.LastError = sendErr.Error()
+
+	if delivery.Attempts >= s.maxAttempts {
+		return s.repo.MoveToDeadLetter(ctx, delivery, now)
+	}
+
+	next := now.Add(s.backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+	return s.repo.RescheduleDelivery(ctx, delivery)
+}
+
+func (s *Service) send(ctx context.Context, subscription Subscription, delivery Delivery) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Id", delivery.Id.String())
+	request.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	request.Header.Set("X-Webhook-Attempt", strconv.Itoa(delivery.Attempts+1))
+	request.Header.Set("X-Signature-256", Sign(subscription.Secret, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with %v", response.Status)
+	}
+
+	return nil
+}
+
+// Run dispatches due deliveries every interval until ctx is cancelled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Dispatch(ctx, time.Now().UTC()); err != nil {
+				log.Println("Error dispatching webhooks:", err)
+			}
+		}
+	}
+}
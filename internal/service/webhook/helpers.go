@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"github.com/google/uuid"
+	"net/url"
+	"time"
+)
+
+const maxBackoff = time.Hour
+
+// Sign returns the value of the X-Signature-256 header sent with body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func validateId(id string, name string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errs.Validation("Invalid " + name)
+	}
+
+	return nil
+}
+
+func validateUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(rawUrl) > 2048 {
+		return errs.Validation("Invalid url")
+	}
+
+	return nil
+}
+
+func validateSecret(secret string) error {
+	if secret != "" && (len(secret) < 16 || len(secret) > 100) {
+		return errs.Validation("Secret must be 16 to 100 characters long")
+	}
+
+	return nil
+}
+
+func validateEventTypes(eventTypes []event.Type) error {
+	for _, eventType := range eventTypes {
+		if !event.OwnerVisible(eventType) {
+			return errs.Validation("Unsupported event type " + string(eventType))
+		}
+	}
+
+	return nil
+}
+
+func validatePagination(limit int, offset int) error {
+	if limit < 0 || limit > 50 {
+		return errs.Validation("Invalid limit value")
+	}
+
+	if offset < 0 {
+		return errs.Validation("Invalid offset value")
+	}
+
+	return nil
+}
+
+// checkManager allows only members that manage the organization to change its
+// webhooks.
+func (s *Service) checkManager(ctx context.Context, username string, organizationId string) error {
+	if username == "" {
+		return errs.Unauthorized("Username is required")
+	}
+
+	userExists, err := s.access.UserExists(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !userExists {
+		return errs.Unauthorized("Unauthorized user")
+	}
+
+	allowed, err := s.access.Can(ctx, username, access.PermissionOrganizationManage, organizationId)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errs.Forbidden("User is not allowed to manage the organization")
+	}
+
+	return nil
+}
+
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.InTx(ctx, s.repo, fn)
+	if errors.Is(err, repository.ErrConflict) {
+		return errs.Conflict("Webhook was modified concurrently, retry the request")
+	}
+
+	return err
+}
+
+// backoff returns the delay before the next attempt after attempts failures.
+func (s *Service) backoff(attempts int) time.Duration {
+	delay := s.baseDelay
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"github.com/google/uuid"
+	"time"
+)
+
+type Subscription struct {
+	Id             uuid.UUID    `json:"id"`
+	OrganizationId uuid.UUID    `json:"organizationId"`
+	Url            string       `json:"url" binding:"required"`
+	Secret         string       `json:"secret,omitempty"`
+	EventTypes     []event.Type `json:"eventTypes"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+type Delivery struct {
+	Id             uuid.UUID       `json:"id"`
+	SubscriptionId uuid.UUID       `json:"subscriptionId"`
+	EventId        int64           `json:"eventId"`
+	EventType      event.Type      `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	FailedAt       *time.Time      `json:"failedAt,omitempty"`
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"context"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"time"
+)
+
+type Repository interface {
+	repository.Transactor
+	CreateSubscription(ctx context.Context, subscription Subscription) (Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionId string) (Subscription, error)
+	ListSubscriptions(ctx context.Context, organizationId string, limit int, offset int) ([]Subscription, error)
+	// ListSubscribers returns the organization's subscriptions that accept
+	// eventType, including those with no event type filter.
+	ListSubscribers(ctx context.Context, organizationId string, eventType event.Type) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionId string) error
+	// EnqueueDelivery ignores a delivery of an event the subscription already has.
+	EnqueueDelivery(ctx context.Context, delivery Delivery) error
+	// ClaimDueDeliveries leases undelivered deliveries scheduled by now by moving
+	// their next attempt to leaseUntil, so other dispatchers skip them while they
+	// are sent and pick them up again if the claimer stops before recording.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]Delivery, error)
+	MarkDelivered(ctx context.Context, deliveryId string, at time.Time) error
+	RescheduleDelivery(ctx context.Context, delivery Delivery) error
+	MoveToDeadLetter(ctx context.Context, delivery Delivery, failedAt time.Time) error
+	ListDeadLetters(ctx context.Context, organizationId string, limit int, offset int) ([]Delivery, error)
+	// GetDeadLetter finds a dead letter of a subscription of the organization.
+	GetDeadLetter(ctx context.Context, organizationId string, deliveryId string) (Delivery, error)
+	// Requeue moves a dead letter back into the delivery queue.
+	Requeue(ctx context.Context, delivery Delivery) error
+}
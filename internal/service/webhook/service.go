@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"net/http"
+	"time"
+)
+
+type Service struct {
+	repo        Repository
+	access      *access.Service
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+func NewService(repo Repository, access *access.Service, client *http.Client) *Service {
+	return &Service{
+		repo:        repo,
+		access:      access,
+		client:      client,
+		maxAttempts: 8,
+		baseDelay:   30 * time.Second,
+	}
+}
@@ -0,0 +1,312 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/app/commands"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository/memory"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/access"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	secret   string
+	received []event.Event
+	invalid  int
+	// during runs before a request is answered, outside of the lock.
+	during func()
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rc.during != nil {
+		rc.during()
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	if r.Header.Get("X-Signature-256") != webhook.Sign(rc.secret, body) {
+		rc.invalid++
+	}
+
+	if rc.status == http.StatusOK {
+		var e event.Event
+		_ = json.Unmarshal(body, &e)
+		rc.received = append(rc.received, e)
+	}
+
+	w.WriteHeader(rc.status)
+}
+
+func (rc *receiver) respond(status int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.status = status
+}
+
+type fixture struct {
+	router         *gin.Engine
+	service        *webhook.Service
+	repo           webhook.Repository
+	organizationId uuid.UUID
+	receiver       *receiver
+	url            string
+}
+
+func newFixture(t *testing.T) fixture {
+	gin.SetMode(gin.TestMode)
+
+	store := memory.NewStore()
+	organizationId := store.AddOrganization("org")
+	store.AddResponsible(organizationId, store.AddEmployee("owner"))
+	store.AddEmployee("stranger")
+
+	rc := &receiver{status: http.StatusOK, secret: "0123456789abcdef"}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	repo := memory.NewWebhookRepository(store)
+	service := webhook.NewService(repo, access.NewService(memory.NewAccessRepository(store)), server.Client())
+	cmd := commands.NewCommander(nil, nil, nil, service, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	router.GET("/organizations/:organizationId/webhooks", cmd.ListWebhooks)
+	router.POST("/organizations/:organizationId/webhooks", cmd.AddWebhook)
+	router.DELETE("/organizations/:organizationId/webhooks/:webhookId", cmd.DeleteWebhook)
+	router.GET("/organizations/:organizationId/webhooks/dead-letters", cmd.WebhookDeadLetters)
+	router.POST("/organizations/:organizationId/webhooks/dead-letters/:deliveryId/redeliver", cmd.RedeliverWebhook)
+
+	return fixture{router: router, service: service, repo: repo, organizationId: uuid.MustParse(organizationId), receiver: rc, url: server.URL}
+}
+
+func (f fixture) do(t *testing.T, method string, target string, body any, out any) int {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	f.router.ServeHTTP(recorder, httptest.NewRequest(method, "/organizations/"+f.organizationId.String()+"/webhooks"+target, &payload))
+
+	if out != nil && recorder.Code < 300 {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", recorder.Body.String(), err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func (f fixture) subscribe(t *testing.T) webhook.Subscription {
+	t.Helper()
+
+	var created webhook.Subscription
+	code := f.do(t, http.MethodPost, "?username=owner", gin.H{
+		"url":        f.url,
+		"secret":     f.receiver.secret,
+		"eventTypes": []event.Type{event.BidPublished},
+	}, &created)
+	if code != http.StatusCreated {
+		t.Fatalf("subscribe code = %d, want %d", code, http.StatusCreated)
+	}
+
+	return created
+}
+
+func (f fixture) enqueue(t *testing.T, id int64, eventType event.Type, organizationId uuid.UUID) {
+	t.Helper()
+
+	err := f.service.Enqueue(context.Background(), event.Event{Id: id, Type: eventType, OrganizationId: organizationId, TenderId: uuid.New(), Payload: json.RawMessage(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubscriptionRequiresResponsible(t *testing.T) {
+	f := newFixture(t)
+
+	body := gin.H{"url": f.url, "eventTypes": []event.Type{event.BidPublished}}
+	if code := f.do(t, http.MethodPost, "?username=stranger", body, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	body["eventTypes"] = []event.Type{event.BidCreated}
+	if code := f.do(t, http.MethodPost, "?username=owner", body, nil); code != http.StatusBadRequest {
+		t.Fatalf("unpublished bid events code = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	created := f.subscribe(t)
+
+	var subscriptions []webhook.Subscription
+	if code := f.do(t, http.MethodGet, "?username=owner", nil, &subscriptions); code != http.StatusOK {
+		t.Fatalf("list code = %d, want %d", code, http.StatusOK)
+	}
+	if len(subscriptions) != 1 || subscriptions[0].Id != created.Id || subscriptions[0].Secret != "" {
+		t.Fatalf("subscriptions = %+v", subscriptions)
+	}
+
+	if code := f.do(t, http.MethodDelete, "/"+created.Id.String()+"?username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("delete code = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestDeliveryRetriesWithBackoff(t *testing.T) {
+	f := newFixture(t)
+	f.subscribe(t)
+
+	f.enqueue(t, 1, event.BidPublished, f.organizationId)
+	f.enqueue(t, 1, event.BidPublished, f.organizationId)
+	f.enqueue(t, 2, event.BidCreated, f.organizationId)
+	f.enqueue(t, 3, event.BidPublished, uuid.New())
+
+	now := time.Now().UTC().Add(time.Minute)
+	f.receiver.respond(http.StatusInternalServerError)
+	if delivered, err := f.service.Dispatch(context.Background(), now); err != nil || delivered != 0 {
+		t.Fatalf("failing dispatch = %d, %v", delivered, err)
+	}
+
+	f.receiver.respond(http.StatusOK)
+	if delivered, err := f.service.Dispatch(context.Background(), now.Add(10*time.Second)); err != nil || delivered != 0 {
+		t.Fatalf("dispatch before backoff = %d, %v", delivered, err)
+	}
+
+	if delivered, err := f.service.Dispatch(context.Background(), now.Add(time.Minute)); err != nil || delivered != 1 {
+		t.Fatalf("dispatch after backoff = %d, %v", delivered, err)
+	}
+
+	if len(f.receiver.received) != 1 || f.receiver.received[0].Id != 1 || f.receiver.invalid != 0 {
+		t.Fatalf("received = %+v, invalid signatures = %d", f.receiver.received, f.receiver.invalid)
+	}
+}
+
+func TestClaimedDeliveryWaitsForLease(t *testing.T) {
+	f := newFixture(t)
+	f.subscribe(t)
+	f.enqueue(t, 1, event.BidPublished, f.organizationId)
+
+	now := time.Now().UTC().Add(time.Minute)
+	concurrent, concurrentErr := -1, error(nil)
+	f.receiver.during = func() {
+		concurrent, concurrentErr = f.service.Dispatch(context.Background(), now)
+	}
+	if delivered, err := f.service.Dispatch(context.Background(), now); err != nil || delivered != 1 {
+		t.Fatalf("dispatch = %d, %v", delivered, err)
+	}
+	if concurrentErr != nil || concurrent != 0 {
+		t.Fatalf("dispatch while sending = %d, %v", concurrent, concurrentErr)
+	}
+	f.receiver.during = nil
+
+	// A dispatcher that stops after claiming leaves the delivery to the others
+	// once the lease runs out.
+	f.enqueue(t, 2, event.BidPublished, f.organizationId)
+	claimed, err := f.repo.ClaimDueDeliveries(context.Background(), now, now.Add(time.Hour), 20)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("claimed = %+v, %v", claimed, err)
+	}
+
+	if delivered, err := f.service.Dispatch(context.Background(), now.Add(time.Minute)); err != nil || delivered != 0 {
+		t.Fatalf("dispatch during lease = %d, %v", delivered, err)
+	}
+	if delivered, err := f.service.Dispatch(context.Background(), now.Add(2*time.Hour)); err != nil || delivered != 1 {
+		t.Fatalf("dispatch after lease = %d, %v", delivered, err)
+	}
+
+	if len(f.receiver.received) != 2 {
+		t.Fatalf("received = %+v", f.receiver.received)
+	}
+}
+
+func TestMissingSubscriptionSkipsOnlyItsDelivery(t *testing.T) {
+	f := newFixture(t)
+	f.subscribe(t)
+
+	var approvals webhook.Subscription
+	code := f.do(t, http.MethodPost, "?username=owner", gin.H{
+		"url":        f.url,
+		"secret":     f.receiver.secret,
+		"eventTypes": []event.Type{event.BidApproved},
+	}, &approvals)
+	if code != http.StatusCreated {
+		t.Fatalf("subscribe code = %d, want %d", code, http.StatusCreated)
+	}
+
+	f.enqueue(t, 1, event.BidPublished, f.organizationId)
+	f.enqueue(t, 2, event.BidApproved, f.organizationId)
+	f.enqueue(t, 3, event.BidPublished, f.organizationId)
+
+	// The second subscription is deleted after the batch is claimed.
+	f.receiver.during = func() {
+		f.receiver.during = nil
+		if err := f.repo.DeleteSubscription(context.Background(), approvals.Id.String()); err != nil {
+			t.Error(err)
+		}
+	}
+
+	now := time.Now().UTC().Add(time.Minute)
+	if delivered, err := f.service.Dispatch(context.Background(), now); err != nil || delivered != 2 {
+		t.Fatalf("dispatch = %d, %v", delivered, err)
+	}
+
+	if len(f.receiver.received) != 2 || f.receiver.received[0].Id != 1 || f.receiver.received[1].Id != 3 {
+		t.Fatalf("received = %+v", f.receiver.received)
+	}
+}
+
+func TestExhaustedDeliveryIsRedelivered(t *testing.T) {
+	f := newFixture(t)
+	f.subscribe(t)
+	f.enqueue(t, 1, event.BidPublished, f.organizationId)
+
+	f.receiver.respond(http.StatusServiceUnavailable)
+	now := time.Now().UTC()
+	for range 8 {
+		now = now.Add(2 * time.Hour)
+		if _, err := f.service.Dispatch(context.Background(), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var deadLetters []webhook.Delivery
+	if code := f.do(t, http.MethodGet, "/dead-letters?username=owner", nil, &deadLetters); code != http.StatusOK {
+		t.Fatalf("dead letters code = %d, want %d", code, http.StatusOK)
+	}
+	if len(deadLetters) != 1 || deadLetters[0].Attempts != 8 || deadLetters[0].LastError == "" {
+		t.Fatalf("dead letters = %+v", deadLetters)
+	}
+
+	if code := f.do(t, http.MethodPost, "/dead-letters/"+deadLetters[0].Id.String()+"/redeliver?username=stranger", nil, nil); code != http.StatusForbidden {
+		t.Fatalf("stranger redeliver code = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if code := f.do(t, http.MethodPost, "/dead-letters/"+deadLetters[0].Id.String()+"/redeliver?username=owner", nil, nil); code != http.StatusOK {
+		t.Fatalf("redeliver code = %d, want %d", code, http.StatusOK)
+	}
+
+	f.receiver.respond(http.StatusOK)
+	if delivered, err := f.service.Dispatch(context.Background(), now); err != nil || delivered != 1 {
+		t.Fatalf("redelivery dispatch = %d, %v", delivered, err)
+	}
+
+	if code := f.do(t, http.MethodGet, "/dead-letters?username=owner", nil, &deadLetters); code != http.StatusOK || len(deadLetters) != 0 {
+		t.Fatalf("dead letters after redelivery: code = %d, %+v", code, deadLetters)
+	}
+}
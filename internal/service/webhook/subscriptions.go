@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/repository"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/errs"
+	"git.codenrock.com/avito-testirovanie-na-backend-1270/cnrprod1725726738-team-78269/zadanie-6105/internal/service/event"
+	"github.com/google/uuid"
+)
+
+// Subscribe registers a webhook for the organization. The returned
+// subscription is the only place its secret is ever shown.
+func (s *Service) Subscribe(ctx context.Context, organizationId string, username string, subscription Subscription) (Subscription, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := validateUrl(subscription.Url); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := validateSecret(subscription.Secret); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := validateEventTypes(subscription.EventTypes); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return Subscription{}, err
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return Subscription{}, err
+		}
+		subscription.Secret = secret
+	}
+
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []event.Type{}
+	}
+
+	subscription.OrganizationId = uuid.MustParse(organizationId)
+
+	return s.repo.CreateSubscription(ctx, subscription)
+}
+
+func (s *Service) List(ctx context.Context, organizationId string, username string, limit int, offset int) ([]Subscription, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return nil, err
+	}
+
+	if err := validatePagination(limit, offset); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx, organizationId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return append([]Subscription{}, subscriptions...), nil
+}
+
+func (s *Service) Unsubscribe(ctx context.Context, organizationId string, subscriptionId string, username string) (Subscription, error) {
+	if err := validateId(organizationId, "organizationId"); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := validateId(subscriptionId, "webhookId"); err != nil {
+		return Subscription{}, err
+	}
+
+	if err := s.checkManager(ctx, username, organizationId); err != nil {
+		return Subscription{}, err
+	}
+
+	var deleted Subscription
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		subscription, err := s.repo.GetSubscription(ctx, subscriptionId)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && subscription.OrganizationId.String() != organizationId) {
+			return errs.NotFound("Webhook not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		deleted = subscription
+		deleted.Secret = ""
+
+		return s.repo.DeleteSubscription(ctx, subscriptionId)
+	})
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return deleted, nil
+}